@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type AdminConfig struct {
+	Token              string
+	LogLevelTimeout    time.Duration
+	LogLevelMaxTimeout time.Duration
+}
+
+func GetAdminConfig() AdminConfig {
+	return AdminConfig{
+		Token:              os.Getenv("ADMIN_TOKEN"),
+		LogLevelTimeout:    getEnvDuration("LOG_LEVEL_OVERRIDE_TIMEOUT", 15*time.Minute),
+		LogLevelMaxTimeout: getEnvDuration("LOG_LEVEL_OVERRIDE_MAX_TIMEOUT", 24*time.Hour),
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type APIConfig struct {
+	LegacyRoutes       bool
+	LegacyRoutesSunset time.Time
+	ValidateRequests   bool
+	ValidateResponses  bool
+	StrictJSON         bool
+	MaxBodySize        int64
+	PublicBaseURL      string
+	JSONCodec          string
+}
+
+func GetAPIConfig() APIConfig {
+	var sunset time.Time
+	if value := os.Getenv("LEGACY_ROUTES_SUNSET"); value != "" {
+		parsed, err := http.ParseTime(value)
+		if err != nil {
+			log.Warn().Err(err).Str("value", value).Msg("Invalid LEGACY_ROUTES_SUNSET, expected an HTTP date")
+		}
+		sunset = parsed
+	}
+
+	return APIConfig{
+		LegacyRoutes:       getEnv("LEGACY_ROUTES", "true") == "true",
+		LegacyRoutesSunset: sunset,
+		ValidateRequests:   getEnv("OPENAPI_VALIDATE_REQUESTS", "true") == "true",
+		ValidateResponses:  getEnv("OPENAPI_VALIDATE_RESPONSES", "false") == "true",
+		StrictJSON:         getEnv("STRICT_JSON", "true") == "true",
+		MaxBodySize:        int64(getEnvInt("MAX_BODY_SIZE", 1<<20)),
+		PublicBaseURL:      getEnv("PUBLIC_BASE_URL", ""),
+		JSONCodec:          getEnv("JSON_CODEC", "std"),
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+type CompressionConfig struct {
+	Enabled      bool
+	MinSize      int
+	ContentTypes []string
+	GzipRequests bool
+}
+
+func GetCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		Enabled: getEnv("COMPRESSION_ENABLED", "true") == "true",
+		MinSize: getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		ContentTypes: getEnvList("COMPRESSION_CONTENT_TYPES", []string{
+			"application/json",
+			"application/vnd.api+json",
+			"application/x-ndjson",
+			"application/xml",
+			"application/yaml",
+			"application/javascript",
+			"text/*",
+		}),
+		GzipRequests: getEnv("GZIP_REQUESTS", "true") == "true",
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+// CORSConfig configures cross-origin requests from browsers. CORS is disabled
+// while AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func GetCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", nil),
+		AllowedMethods: getEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE"}),
+		AllowedHeaders: getEnvList("CORS_ALLOWED_HEADERS", []string{
+			"Accept",
+			"Accept-Language",
+			"Authorization",
+			"Content-Encoding",
+			"Content-Type",
+			"X-API-Key",
+			"X-Request-ID",
+		}),
+		ExposedHeaders: getEnvList("CORS_EXPOSED_HEADERS", []string{
+			"Content-Language",
+			"Deprecation",
+			"Link",
+			"Location",
+			"RateLimit-Limit",
+			"RateLimit-Policy",
+			"RateLimit-Remaining",
+			"RateLimit-Reset",
+			"Retry-After",
+			"Sunset",
+			"X-Request-ID",
+		}),
+		AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "false") == "true",
+		MaxAge:           getEnvDuration("CORS_MAX_AGE", 10*time.Minute),
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelOverride is a temporary log level that reverts when it expires.
+type LevelOverride struct {
+	Level     zerolog.Level
+	ExpiresAt time.Time
+	timer     *time.Timer
+}
+
+// LogLevelController changes log levels at runtime, globally or per component tag
+// such as "[BookRepository]", and reverts every change after its timeout.
+type LogLevelController struct {
+	mu         sync.RWMutex
+	base       zerolog.Level
+	global     *LevelOverride
+	components map[string]*LevelOverride
+}
+
+var _ zerolog.Hook = (*LogLevelController)(nil)
+
+func NewLogLevelController(base zerolog.Level) *LogLevelController {
+	return &LogLevelController{base: base, components: map[string]*LevelOverride{}}
+}
+
+// Base returns the level configured at startup.
+func (c *LogLevelController) Base() zerolog.Level {
+	return c.base
+}
+
+// Snapshot returns the current global override and component overrides.
+func (c *LogLevelController) Snapshot() (*LevelOverride, map[string]LevelOverride) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var global *LevelOverride
+	if c.global != nil {
+		g := *c.global
+		global = &g
+	}
+
+	components := make(map[string]LevelOverride, len(c.components))
+	for name, override := range c.components {
+		components[name] = *override
+	}
+	return global, components
+}
+
+// SetLevel overrides the level of a component, or the global level when component is empty, for the given duration.
+func (c *LogLevelController) SetLevel(component string, level zerolog.Level, duration time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	override := &LevelOverride{Level: level, ExpiresAt: time.Now().Add(duration)}
+	override.timer = time.AfterFunc(duration, func() { c.revert(component, override) })
+
+	if component == "" {
+		if c.global != nil {
+			c.global.timer.Stop()
+		}
+		c.global = override
+	} else {
+		if existing, ok := c.components[component]; ok {
+			existing.timer.Stop()
+		}
+		c.components[component] = override
+	}
+
+	c.apply()
+	return override.ExpiresAt
+}
+
+// revert removes the override only if it has not been replaced in the meantime.
+func (c *LogLevelController) revert(component string, override *LevelOverride) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if component == "" {
+		if c.global == override {
+			c.global = nil
+		}
+	} else if c.components[component] == override {
+		delete(c.components, component)
+	}
+
+	c.apply()
+}
+
+// globalLevel returns the level applied to events without a component override. Callers must hold the lock.
+func (c *LogLevelController) globalLevel() zerolog.Level {
+	if c.global != nil {
+		return c.global.Level
+	}
+	return c.base
+}
+
+// apply sets the zerolog global level to the most verbose level in use so
+// that component overrides can be enabled. Callers must hold the lock.
+func (c *LogLevelController) apply() {
+	level := c.globalLevel()
+	for _, override := range c.components {
+		if override.Level < level {
+			level = override.Level
+		}
+	}
+	zerolog.SetGlobalLevel(level)
+}
+
+// Run implements zerolog.Hook and discards events below the level of their component.
+func (c *LogLevelController) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	minLevel := c.globalLevel()
+	if override, ok := c.components[componentOf(msg)]; ok {
+		minLevel = override.Level
+	}
+
+	if level < minLevel {
+		e.Discard()
+	}
+}
+
+// componentOf extracts the component name from a message tagged like "[BookHandler] ...".
+func componentOf(msg string) string {
+	if !strings.HasPrefix(msg, "[") {
+		return ""
+	}
+	end := strings.Index(msg, "]")
+	if end < 0 {
+		return ""
+	}
+	return msg[1:end]
+}
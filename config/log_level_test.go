@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestController returns a controller at info level with a logger that writes into the returned buffer.
+func newTestController(t *testing.T) (*LogLevelController, zerolog.Logger, *bytes.Buffer) {
+	previous := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(previous) })
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	controller := NewLogLevelController(zerolog.InfoLevel)
+	var buffer bytes.Buffer
+	return controller, zerolog.New(&buffer).Hook(controller), &buffer
+}
+
+// logged reports whether the message is written by logger at level, resetting the buffer.
+func logged(logger zerolog.Logger, buffer *bytes.Buffer, level zerolog.Level, msg string) bool {
+	buffer.Reset()
+	logger.WithLevel(level).Msg(msg)
+	return buffer.Len() > 0
+}
+
+func TestLogLevelController(t *testing.T) {
+	const short = 30 * time.Millisecond
+
+	// Define test for case Global Override Applies And Reverts
+	t.Run("Global Override Applies And Reverts", func(t *testing.T) {
+		controller, logger, buffer := newTestController(t)
+
+		controller.SetLevel("", zerolog.DebugLevel, short)
+		assert.True(t, logged(logger, buffer, zerolog.DebugLevel, "[BookHandler] debug"))
+		assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+
+		assert.Eventually(t, func() bool {
+			global, _ := controller.Snapshot()
+			return global == nil
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, zerolog.InfoLevel, zerolog.GlobalLevel())
+		assert.False(t, logged(logger, buffer, zerolog.DebugLevel, "[BookHandler] debug"))
+	})
+
+	// Define test for case Component Override Raises One Component
+	t.Run("Component Override Raises One Component", func(t *testing.T) {
+		controller, logger, buffer := newTestController(t)
+
+		controller.SetLevel("BookRepository", zerolog.DebugLevel, short)
+		assert.True(t, logged(logger, buffer, zerolog.DebugLevel, "[BookRepository] debug"))
+		assert.False(t, logged(logger, buffer, zerolog.DebugLevel, "[BookHandler] debug"))
+		assert.False(t, logged(logger, buffer, zerolog.DebugLevel, "untagged debug"))
+
+		assert.Eventually(t, func() bool {
+			_, components := controller.Snapshot()
+			return len(components) == 0
+		}, time.Second, 5*time.Millisecond)
+		assert.False(t, logged(logger, buffer, zerolog.DebugLevel, "[BookRepository] debug"))
+		assert.Equal(t, zerolog.InfoLevel, zerolog.GlobalLevel())
+	})
+
+	// Define test for case Component Override Lowers One Component
+	t.Run("Component Override Lowers One Component", func(t *testing.T) {
+		controller, logger, buffer := newTestController(t)
+
+		controller.SetLevel("BookHandler", zerolog.ErrorLevel, short)
+		assert.False(t, logged(logger, buffer, zerolog.InfoLevel, "[BookHandler] info"))
+		assert.True(t, logged(logger, buffer, zerolog.ErrorLevel, "[BookHandler] error"))
+		assert.True(t, logged(logger, buffer, zerolog.InfoLevel, "[BookService] info"))
+
+		assert.Eventually(t, func() bool {
+			return logged(logger, buffer, zerolog.InfoLevel, "[BookHandler] info")
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	// Define test for case Second Override Cancels The First Timer
+	t.Run("Second Override Cancels The First Timer", func(t *testing.T) {
+		for _, component := range []string{"", "BookRepository"} {
+			controller, _, _ := newTestController(t)
+
+			controller.SetLevel(component, zerolog.DebugLevel, short)
+			expiresAt := controller.SetLevel(component, zerolog.WarnLevel, time.Hour)
+
+			// Well past the first timer, which must neither revert nor replace the second override
+			time.Sleep(3 * short)
+			global, components := controller.Snapshot()
+			override := global
+			if component != "" {
+				if o, ok := components[component]; ok {
+					override = &o
+				}
+			}
+			if assert.NotNil(t, override, "component %q", component) {
+				assert.Equal(t, zerolog.WarnLevel, override.Level)
+				assert.Equal(t, expiresAt, override.ExpiresAt)
+			}
+		}
+	})
+
+	// Define test for case Component Tag Parsing
+	t.Run("Component Tag Parsing", func(t *testing.T) {
+		tests := map[string]string{
+			"[BookHandler] Successfully got the data.": "BookHandler",
+			"[] empty":             "",
+			"no tag":               "",
+			"[unterminated":        "",
+			"text [NotAtTheStart]": "",
+		}
+		for msg, want := range tests {
+			assert.Equal(t, want, componentOf(msg), msg)
+		}
+	})
+}
@@ -1,32 +1,113 @@
 package config
 
 import (
+	"io"
 	"os"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// InitializeLogger initializes the logging configuration
-func InitializeLogger() {
-	logLevel := os.Getenv("LOG_LEVEL")
-	if logLevel == "" {
-		logLevel = "info"
+// Version is the application version, overridden at build time with
+// -ldflags "-X RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config.Version=1.2.3".
+var Version = "dev"
+
+type LoggerConfig struct {
+	Level          string
+	Format         string
+	File           string
+	FileMaxSize    int
+	FileMaxAge     int
+	FileMaxBackups int
+	SampleInfo     uint32
+	ServiceName    string
+	ServiceVersion string
+}
+
+func GetLoggerConfig() LoggerConfig {
+	return LoggerConfig{
+		Level:          getEnv("LOG_LEVEL", "info"),
+		Format:         getEnv("LOG_FORMAT", "console"),
+		File:           os.Getenv("LOG_FILE"),
+		FileMaxSize:    getEnvInt("LOG_FILE_MAX_SIZE", 100),
+		FileMaxAge:     getEnvInt("LOG_FILE_MAX_AGE", 7),
+		FileMaxBackups: getEnvInt("LOG_FILE_MAX_BACKUPS", 5),
+		SampleInfo:     uint32(getEnvInt("LOG_SAMPLE_INFO", 0)),
+		ServiceName:    getEnv("SERVICE_NAME", "book-api"),
+		ServiceVersion: getEnv("SERVICE_VERSION", Version),
 	}
+}
 
-	var level zerolog.Level
+// ParseLogLevel converts a level name into a zerolog level, falling back to info.
+func ParseLogLevel(logLevel string) zerolog.Level {
 	switch logLevel {
 	case "debug":
-		level = zerolog.DebugLevel
+		return zerolog.DebugLevel
 	case "warn":
-		level = zerolog.WarnLevel
+		return zerolog.WarnLevel
 	case "error":
-		level = zerolog.ErrorLevel
+		return zerolog.ErrorLevel
 	default:
-		level = zerolog.InfoLevel
+		return zerolog.InfoLevel
+	}
+}
+
+// InitializeLogger initializes the logging configuration and returns the
+// controller used to change log levels at runtime.
+func InitializeLogger() *LogLevelController {
+	logConfig := GetLoggerConfig()
+
+	levels := NewLogLevelController(ParseLogLevel(logConfig.Level))
+	zerolog.SetGlobalLevel(levels.Base())
+
+	var stderr io.Writer = os.Stderr
+	if logConfig.Format != "json" {
+		stderr = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339Nano}
+	}
+
+	writers := []io.Writer{stderr}
+
+	// The log file is always written as JSON so it can be shipped as-is
+	if logConfig.File != "" {
+		writers = append(writers, &lumberjack.Logger{
+			Filename:   logConfig.File,
+			MaxSize:    logConfig.FileMaxSize,
+			MaxAge:     logConfig.FileMaxAge,
+			MaxBackups: logConfig.FileMaxBackups,
+		})
+	}
+
+	hostname, _ := os.Hostname()
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(writers...)).With().
+		Timestamp().
+		Str("service", logConfig.ServiceName).
+		Str("version", logConfig.ServiceVersion).
+		Str("hostname", hostname).
+		Logger()
+
+	// Keep only one in every N info events, warnings and errors are never sampled
+	if logConfig.SampleInfo > 1 {
+		logger = logger.Sample(zerolog.LevelSampler{
+			InfoSampler: &zerolog.BasicSampler{N: logConfig.SampleInfo},
+		})
 	}
 
-	zerolog.SetGlobalLevel(level)
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339Nano})
+	log.Logger = logger.Hook(levels)
+
+	// Fall back to the global logger when zerolog.Ctx is called on a context without one
+	zerolog.DefaultContextLogger = &log.Logger
+	return levels
+}
+
+type AccessLogConfig struct {
+	ExcludePaths []string
+}
+
+func GetAccessLogConfig() AccessLogConfig {
+	return AccessLogConfig{
+		ExcludePaths: getEnvList("ACCESS_LOG_EXCLUDE_PATHS", []string{"/healthz", "/readyz"}),
+	}
 }
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitConfig configures the requests allowed per client. Read routes
+// (GET and HEAD) and write routes have separate limits over the same window.
+type RateLimitConfig struct {
+	Enabled       bool
+	ReadRequests  int
+	WriteRequests int
+	Window        time.Duration
+	APIKeys       []string
+	ExcludePaths  []string
+}
+
+// GetRateLimitConfig returns an error when the limits or the window are not
+// above 0, which would leave the buckets without a refill rate.
+func GetRateLimitConfig() (RateLimitConfig, error) {
+	cfg := RateLimitConfig{
+		Enabled:       getEnv("RATE_LIMIT_ENABLED", "true") == "true",
+		ReadRequests:  getEnvInt("RATE_LIMIT_READ_REQUESTS", 300),
+		WriteRequests: getEnvInt("RATE_LIMIT_WRITE_REQUESTS", 60),
+		Window:        getEnvDuration("RATE_LIMIT_WINDOW", time.Minute),
+		APIKeys:       getEnvList("RATE_LIMIT_API_KEYS", nil),
+		ExcludePaths:  getEnvList("RATE_LIMIT_EXCLUDE_PATHS", []string{"/healthz", "/readyz", "/metrics"}),
+	}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	switch {
+	case cfg.ReadRequests <= 0:
+		return cfg, fmt.Errorf("RATE_LIMIT_READ_REQUESTS must be greater than 0, got %d", cfg.ReadRequests)
+	case cfg.WriteRequests <= 0:
+		return cfg, fmt.Errorf("RATE_LIMIT_WRITE_REQUESTS must be greater than 0, got %d", cfg.WriteRequests)
+	case cfg.Window <= 0:
+		return cfg, fmt.Errorf("RATE_LIMIT_WINDOW must be greater than 0, got %s", cfg.Window)
+	}
+	return cfg, nil
+}
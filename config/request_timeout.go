@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RequestTimeoutConfig holds the deadline of every request. Routes overrides
+// it per route, keyed by method and route path such as "GET /api/v1/books/:id",
+// where 0 disables the deadline. REQUEST_TIMEOUT_ROUTES entries are added on
+// top of defaultRequestTimeoutRoutes and win when they name the same route.
+type RequestTimeoutConfig struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+// Streams take as long as the list is, their writes are bound by the server write timeout instead
+var defaultRequestTimeoutRoutes = []string{
+	"GET /api/v1/books/stream=0",
+	"GET /books/stream=0",
+}
+
+func GetRequestTimeoutConfig() RequestTimeoutConfig {
+	entries := append(append([]string{}, defaultRequestTimeoutRoutes...), getEnvList("REQUEST_TIMEOUT_ROUTES", nil)...)
+
+	routes := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		route, value, ok := strings.Cut(entry, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if value == "0" {
+			timeout, err = 0, nil
+		}
+		if !ok || !hasPath || err != nil {
+			log.Warn().Str("value", entry).Msg("Invalid REQUEST_TIMEOUT_ROUTES entry, expected METHOD /path=duration")
+			continue
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = timeout
+	}
+
+	return RequestTimeoutConfig{
+		Default: getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		Routes:  routes,
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+type ServerConfig struct {
+	Host              string
+	Port              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	DrainDelay        time.Duration
+	ShutdownTimeout   time.Duration
+	TrustedProxies    []string
+}
+
+func (c *ServerConfig) Address() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.Port)
+}
+
+func GetServerConfig() ServerConfig {
+	return ServerConfig{
+		Host:              os.Getenv("HOST"),
+		Port:              os.Getenv("PORT"),
+		ReadTimeout:       getEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+		DrainDelay:        getEnvDuration("SERVER_DRAIN_DELAY", 5*time.Second),
+		ShutdownTimeout:   getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+		TrustedProxies:    getEnvList("TRUSTED_PROXIES", nil),
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type TLSConfig struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ClientAuth     string
+	MinVersion     string
+	ReloadInterval time.Duration
+}
+
+// Enabled reports whether the server should serve HTTPS.
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+func GetTLSConfig() TLSConfig {
+	return TLSConfig{
+		CertFile:       os.Getenv("TLS_CERT_FILE"),
+		KeyFile:        os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile:   os.Getenv("TLS_CLIENT_CA_FILE"),
+		ClientAuth:     getEnv("TLS_CLIENT_AUTH", "none"),
+		MinVersion:     getEnv("TLS_MIN_VERSION", "1.2"),
+		ReloadInterval: getEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+	}
+}
+
+// LoadTLSConfig builds the server TLS configuration. The certificate, key and
+// client CA bundle are reloaded in the background whenever the files change.
+func LoadTLSConfig(ctx context.Context, tlsConfig TLSConfig) (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(tlsConfig.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth, err := parseClientAuth(tlsConfig.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth != tls.NoClientCert && tlsConfig.ClientCAFile == "" {
+		return nil, fmt.Errorf("TLS_CLIENT_CA_FILE is required when TLS_CLIENT_AUTH is %q", tlsConfig.ClientAuth)
+	}
+	if tlsConfig.ReloadInterval <= 0 {
+		return nil, fmt.Errorf("TLS_RELOAD_INTERVAL must be greater than 0, got %s", tlsConfig.ReloadInterval)
+	}
+
+	reloader := &tlsReloader{config: tlsConfig}
+	if err := reloader.reload(); err != nil {
+		log.Error().Err(err).Msg("Failed to load TLS certificate")
+		return nil, err
+	}
+	go reloader.watch(ctx)
+
+	base := &tls.Config{
+		MinVersion: minVersion,
+		ClientAuth: clientAuth,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			certificate, _ := reloader.current()
+			return certificate, nil
+		},
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		_, clientCAs := reloader.current()
+
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.ClientCAs = clientCAs
+		return config, nil
+	}
+
+	log.Info().Str("min_version", tlsConfig.MinVersion).Str("client_auth", tlsConfig.ClientAuth).Msg("TLS is enabled")
+	return base, nil
+}
+
+// tlsReloader keeps the most recent certificate and client CA pool loaded from disk.
+type tlsReloader struct {
+	config TLSConfig
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTime     time.Time
+}
+
+func (r *tlsReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.certificate, r.clientCAs
+}
+
+func (r *tlsReloader) reload() error {
+	certificate, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.modTime = r.latestModTime()
+	return nil
+}
+
+// latestModTime returns the most recent modification time of the watched files.
+func (r *tlsReloader) latestModTime() time.Time {
+	var latest time.Time
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// watch polls the files and reloads them when they change. A failed reload keeps the previous certificate.
+func (r *tlsReloader) watch(ctx context.Context) {
+	ticker := time.NewTicker(r.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.RLock()
+			changed := r.latestModTime().After(r.modTime)
+			r.mu.RUnlock()
+			if !changed {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				log.Error().Err(err).Msg("Failed to reload TLS certificate, keeping the previous one")
+				continue
+			}
+			log.Info().Msg("TLS certificate reloaded")
+		}
+	}
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS_MIN_VERSION %q, use 1.2 or 1.3", version)
+}
+
+func parseClientAuth(clientAuth string) (tls.ClientAuthType, error) {
+	switch clientAuth {
+	case "none":
+		return tls.NoClientCert, nil
+	case "optional":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS_CLIENT_AUTH %q, use none, optional or require", clientAuth)
+}
@@ -0,0 +1,22 @@
+package config
+
+import "os"
+
+type TracingConfig struct {
+	Exporter       string
+	File           string
+	ServiceName    string
+	ServiceVersion string
+}
+
+// GetTracingConfig reads the tracing settings. The OTLP endpoint and headers are read
+// by the exporter itself from the standard OTEL_EXPORTER_OTLP_* variables, and the
+// sampler by the SDK from OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG.
+func GetTracingConfig() TracingConfig {
+	return TracingConfig{
+		Exporter:       getEnv("OTEL_TRACES_EXPORTER", "none"),
+		File:           os.Getenv("OTEL_TRACES_FILE"),
+		ServiceName:    getEnv("SERVICE_NAME", "book-api"),
+		ServiceVersion: getEnv("SERVICE_VERSION", Version),
+	}
+}
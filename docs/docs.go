@@ -0,0 +1,59 @@
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// Spec is the OpenAPI document describing every route of the API.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// swaggerInitializer points the embedded Swagger UI at our own spec.
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/docs/openapi.json",
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+var swaggerUI = http.StripPrefix("/docs", http.FileServer(http.FS(swaggerFiles.FS)))
+
+// Handler serves the OpenAPI document and the Swagger UI under /docs/*filepath.
+func Handler(c *gin.Context) {
+	switch c.Param("filepath") {
+	case "/openapi.json":
+		c.Data(http.StatusOK, "application/json", Spec)
+	case "/swagger-initializer.js":
+		c.Data(http.StatusOK, "application/javascript", []byte(swaggerInitializer))
+	default:
+		swaggerUI.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// NewRouter loads the OpenAPI document and returns a router that matches
+// requests to its operations for validation.
+func NewRouter() (routers.Router, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return nil, err
+	}
+	return gorillamux.NewRouter(doc)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type AdminHandler struct {
+	Levels *config.LogLevelController
+	Config config.AdminConfig
+}
+
+func NewAdminHandler(levels *config.LogLevelController, adminConfig config.AdminConfig) *AdminHandler {
+	return &AdminHandler{Levels: levels, Config: adminConfig}
+}
+
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	helper.SendSuccessResponse(c, http.StatusOK, "Successfully got the log level.", h.logLevelStatus())
+}
+
+func (h *AdminHandler) UpdateLogLevel(c *gin.Context) {
+	logger := zerolog.Ctx(c.Request.Context())
+
+	var request models.LogLevelRequest
+	if err := helper.BindJSON(c, &request); err != nil {
+		logger.Error().Err(err).Msg("[AdminHandler] Failed to process log level update.")
+		helper.HandleValidationError(c, err)
+		return
+	}
+
+	duration := h.Config.LogLevelTimeout
+	if request.Duration != "" {
+		parsed, err := time.ParseDuration(request.Duration)
+		if err != nil || parsed <= 0 {
+			logger.Error().Err(err).Str("duration", request.Duration).Msg("[AdminHandler] Invalid log level duration.")
+			helper.SendErrorResponse(c, http.StatusBadRequest, "Duration must be a positive duration such as 10m.", nil)
+			return
+		}
+		duration = parsed
+	}
+	if duration > h.Config.LogLevelMaxTimeout {
+		duration = h.Config.LogLevelMaxTimeout
+	}
+
+	component := strings.Trim(request.Component, "[]")
+	level := config.ParseLogLevel(request.Level)
+	expiresAt := h.Levels.SetLevel(component, level, duration)
+
+	helper.SendSuccessResponse(c, http.StatusOK, "Successfully updated the log level.", h.logLevelStatus())
+	logger.Warn().
+		Str("level", level.String()).
+		Str("component", component).
+		Time("expires_at", expiresAt).
+		Msg("[AdminHandler] Log level changed at runtime.")
+}
+
+func (h *AdminHandler) logLevelStatus() models.LogLevelStatus {
+	global, components := h.Levels.Snapshot()
+
+	status := models.LogLevelStatus{
+		Level:      h.Levels.Base().String(),
+		Default:    h.Levels.Base().String(),
+		Components: make(map[string]models.LogLevelOverride, len(components)),
+	}
+	if global != nil {
+		status.Level = global.Level.String()
+		status.ExpiresAt = &global.ExpiresAt
+	}
+	for name, override := range components {
+		status.Components[name] = models.LogLevelOverride{
+			Level:     override.Level.String(),
+			ExpiresAt: override.ExpiresAt,
+		}
+	}
+	return status
+}
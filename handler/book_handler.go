@@ -2,14 +2,17 @@ package handler
 
 import (
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/i18n"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/service"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/tracing"
+	"context"
+	"errors"
 	"net/http"
-	"strconv"
 
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
 
 	"github.com/gin-gonic/gin"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 )
 
 type BookHandler struct {
@@ -21,75 +24,141 @@ func NewBookHandler(service service.BookService) *BookHandler {
 }
 
 func (h *BookHandler) GetAllBooks(c *gin.Context) {
-	books, err := h.Service.GetAllBooks(c.Request.Context())
+	defer tracing.StartGinSpan(c, "BookHandler.GetAllBooks").End()
+	logger := zerolog.Ctx(c.Request.Context())
+
+	fields, ok := helper.GetFieldsParam(c, models.BookType, models.BookFields)
+	if !ok {
+		return
+	}
+
+	page, ok := helper.GetPageParams(c)
+	if !ok {
+		return
+	}
+
+	books, total, err := h.Service.GetAllBooks(c.Request.Context(), fields, page)
 	if err != nil {
-		log.Error().Err(err).Msg("[BookHandler] Failed to get data")
+		logger.Error().Err(err).Msg("[BookHandler] Failed to get data")
 		helper.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get data", nil)
 		return
 	}
 
-	helper.SendSuccessResponse(c, http.StatusOK, "Successfully got all data.", books)
-	log.Info().Msg("[BookHandler] Successfully got all data.")
+	helper.SendListResponse(c, http.StatusOK, "Successfully got all data.", helper.SelectFields(books, fields), models.Pagination{Page: page, Total: total})
+	logger.Info().Msg("[BookHandler] Successfully got all data.")
 }
 
-func (h *BookHandler) GetBookByID(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+// StreamBooks writes every book as a JSON array or as NDJSON while the rows
+// are read, for lists too large to be loaded at once.
+func (h *BookHandler) StreamBooks(c *gin.Context) {
+	defer tracing.StartGinSpan(c, "BookHandler.StreamBooks").End()
+	logger := zerolog.Ctx(c.Request.Context())
+
+	fields, ok := helper.GetFieldsParam(c, models.BookType, models.BookFields)
+	if !ok {
+		return
+	}
+
+	stream, ok := helper.NewListStream(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.StreamBooks(c.Request.Context(), fields, func(book models.Book) error {
+		return stream.Write(helper.SelectFields(book, fields))
+	})
+	if err == nil {
+		err = stream.Close()
+	}
 	if err != nil {
-		log.Error().Err(err).Msg("[BookHandler] Failed to convert ID from URL")
-		helper.SendErrorResponse(c, http.StatusBadRequest, "ID must be a valid number.", nil)
+		if errors.Is(err, context.Canceled) {
+			logger.Warn().Err(err).Msg("[BookHandler] Client went away while streaming data")
+		} else {
+			logger.Error().Err(err).Msg("[BookHandler] Failed to stream data")
+		}
+		if stream.Started() {
+			stream.Abort()
+		}
+		helper.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get data", nil)
 		return
 	}
 
-	book, err := h.Service.GetBookByID(c.Request.Context(), id)
+	logger.Info().Msg("[BookHandler] Successfully streamed all data.")
+}
+
+func (h *BookHandler) GetBookByID(c *gin.Context) {
+	defer tracing.StartGinSpan(c, "BookHandler.GetBookByID").End()
+	logger := zerolog.Ctx(c.Request.Context())
+
+	id, ok := helper.GetIDParam(c)
+	if !ok {
+		return
+	}
+
+	fields, ok := helper.GetFieldsParam(c, models.BookType, models.BookFields)
+	if !ok {
+		return
+	}
+
+	book, err := h.Service.GetBookByID(c.Request.Context(), id, fields)
 	if err != nil {
 		if err.Error() == "errBookNotFound" {
-			log.Error().Err(err).Int("id", id).Msg("[BookHandler] Data with that ID does not exist.")
+			logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Data with that ID does not exist.")
 			helper.SendErrorResponse(c, http.StatusNotFound, "Data with that ID does not exist.", nil)
 			return
 		}
-		log.Error().Err(err).Int("id", id).Msg("[BookHandler] Failed to get data")
+		logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Failed to get data")
 		helper.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get data", nil)
 		return
 	}
 
-	helper.SendSuccessResponse(c, http.StatusOK, "Successfully got the data.", book)
-	log.Info().Int("id", id).Msg("[BookHandler] Successfully got the data.")
+	helper.SendSuccessResponse(c, http.StatusOK, "Successfully got the data.", helper.SelectFields(book, fields))
+	logger.Info().Int("id", id).Msg("[BookHandler] Successfully got the data.")
 }
 
 func (h *BookHandler) CreateBook(c *gin.Context) {
+	defer tracing.StartGinSpan(c, "BookHandler.CreateBook").End()
+	logger := zerolog.Ctx(c.Request.Context())
+
 	var book models.Book
-	if err := c.ShouldBindJSON(&book); err != nil {
-		log.Error().Err(err).Msg("Failed to process input data")
+	if err := helper.BindJSON(c, &book); err != nil {
+		logger.Error().Err(err).Msg("Failed to process input data")
 		helper.HandleValidationError(c, err)
 		return
 	}
 
 	if err := h.Service.CreateBook(c.Request.Context(), &book); err != nil {
 		if err.Error() == "ErrBookExists" {
-			log.Error().Err(err).Msg("[BookHandler] The book with the same title already exists.")
+			logger.Error().Err(err).Msg("[BookHandler] The book with the same title already exists.")
 			helper.SendErrorResponse(c, http.StatusBadRequest, "The book with the same title already exists.", nil)
 			return
 		}
-		log.Error().Err(err).Msg("[BookHandler] Failed to create data")
+		if errors.Is(err, service.ErrYearInFuture) {
+			logger.Error().Err(err).Msg("[BookHandler] Year of publication cannot be in the future.")
+			sendYearInFutureError(c)
+			return
+		}
+		logger.Error().Err(err).Msg("[BookHandler] Failed to create data")
 		helper.SendErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
 
 	helper.SendSuccessResponse(c, http.StatusCreated, "Successfully created data", book)
-	log.Info().Int("id", book.ID).Msgf("[BookHandler] Successfully created data %v", book)
+	logger.Info().Int("id", book.ID).Msgf("[BookHandler] Successfully created data %v", book)
 }
 
 func (h *BookHandler) UpdateBook(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		log.Error().Err(err).Msg("[BookHandler] Failed to convert ID from URL.")
-		helper.SendErrorResponse(c, http.StatusBadRequest, "ID must be a valid number.", nil)
+	defer tracing.StartGinSpan(c, "BookHandler.UpdateBook").End()
+	logger := zerolog.Ctx(c.Request.Context())
+
+	id, ok := helper.GetIDParam(c)
+	if !ok {
 		return
 	}
 
 	var book models.Book
-	if err := c.ShouldBindJSON(&book); err != nil {
-		log.Error().Err(err).Msg("[BookHandler] Failed to process data update.")
+	if err := helper.BindJSON(c, &book); err != nil {
+		logger.Error().Err(err).Msg("[BookHandler] Failed to process data update.")
 		helper.HandleValidationError(c, err)
 		return
 	}
@@ -97,49 +166,59 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	book.ID = id
 
 	if err := h.Service.UpdateBook(c.Request.Context(), &book); err != nil {
+		if errors.Is(err, service.ErrYearInFuture) {
+			logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Year of publication cannot be in the future, cannot update.")
+			sendYearInFutureError(c)
+			return
+		}
 		switch err.Error() {
 		case "errBookNotFound":
-			log.Error().Err(err).Int("id", id).Msg("[BookHandler] Data with that ID does not exist, cannot update.")
+			logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Data with that ID does not exist, cannot update.")
 			helper.SendErrorResponse(c, http.StatusNotFound, "Data with that ID does not exist, cannot update.", nil)
 			return
-		case "errTheYearCannotBeInTheFuture":
-			log.Error().Err(err).Int("id", id).Msg("[BookHandler] Year of publication cannot be in the future, cannot update.")
-			helper.SendErrorResponse(c, http.StatusBadRequest, "Year of publication cannot be in the future, cannot update.", nil)
-			return
 		}
-		log.Error().Err(err).Int("id", id).Msg("[BookHandler] Failed to update data.")
+		logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Failed to update data.")
 		helper.SendErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
 
 	helper.SendSuccessResponse(c, http.StatusOK, "Successfully updated data.", book)
-	log.Info().Int("id", book.ID).Msg("[BookHandler] Successfully updated data.")
+	logger.Info().Int("id", book.ID).Msg("[BookHandler] Successfully updated data.")
 }
 
 func (h *BookHandler) DeleteBook(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		log.Error().Err(err).Msg("[BookHandler] Failed to convert ID from URL.")
-		helper.SendErrorResponse(c, http.StatusBadRequest, "ID must be a valid number.", nil)
+	defer tracing.StartGinSpan(c, "BookHandler.DeleteBook").End()
+	logger := zerolog.Ctx(c.Request.Context())
+
+	id, ok := helper.GetIDParam(c)
+	if !ok {
 		return
 	}
 
 	if err := h.Service.DeleteBook(c.Request.Context(), id); err != nil {
 		switch err.Error() {
 		case "errBookNotFound":
-			log.Error().Err(err).Int("id", id).Msg("[BookHandler] Data with that ID does not exist, you cannot delete it.")
+			logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Data with that ID does not exist, you cannot delete it.")
 			helper.SendErrorResponse(c, http.StatusNotFound, "Data with that ID does not exist, you cannot delete it.", nil)
 			return
 		case "errBooksOlderThan10Years":
-			log.Error().Err(err).Int("id", id).Msg("[BookHandler] Books older than 10 years cannot be deleted.")
+			logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Books older than 10 years cannot be deleted.")
 			helper.SendErrorResponse(c, http.StatusBadRequest, "Books older than 10 years cannot be deleted.", nil)
 			return
 		}
-		log.Error().Err(err).Int("id", id).Msg("[BookHandler] Failed to delete data.")
+		logger.Error().Err(err).Int("id", id).Msg("[BookHandler] Failed to delete data.")
 		helper.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete data.", nil)
 		return
 	}
 
 	helper.SendSuccessResponse(c, http.StatusOK, "Successfully deleted data.", nil)
-	log.Info().Int("id", id).Msg("[BookHandler] Successfully deleted data.")
+	logger.Info().Int("id", id).Msg("[BookHandler] Successfully deleted data.")
+}
+
+// sendYearInFutureError reports service.ErrYearInFuture like the notfuture validation of the year field.
+func sendYearInFutureError(c *gin.Context) {
+	helper.SendValidationErrorResponse(c, []models.ValidationErrorDetail{{
+		Field:   "year",
+		Message: i18n.T(c, "validation.notfuture"),
+	}})
 }
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"context"
+	"database/sql"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+)
+
+type HealthHandler struct {
+	DB       *sql.DB
+	Timeout  time.Duration
+	draining atomic.Bool
+}
+
+func NewHealthHandler(db *sql.DB, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{DB: db, Timeout: timeout}
+}
+
+// SetDraining marks the server as shutting down so readiness starts failing.
+func (h *HealthHandler) SetDraining(draining bool) {
+	h.draining.Store(draining)
+}
+
+// Liveness reports that the process is running, without touching any dependency.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	helper.SendSuccessResponse(c, http.StatusOK, "Service is alive.", models.HealthStatus{Status: healthStatusUp})
+}
+
+// Readiness reports whether the service can accept traffic.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.Timeout)
+	defer cancel()
+
+	checks := map[string]models.HealthCheck{
+		"database": h.checkDatabase(ctx),
+		"schema":   h.checkSchema(ctx),
+		"drain":    h.checkDrain(),
+	}
+
+	status := models.HealthStatus{Status: healthStatusUp, Checks: checks}
+	for _, check := range checks {
+		if check.Status != healthStatusUp {
+			status.Status = healthStatusDown
+		}
+	}
+
+	if status.Status != healthStatusUp {
+		zerolog.Ctx(ctx).Warn().Interface("checks", checks).Msg("[HealthHandler] Service is not ready.")
+		helper.SendErrorResponse(c, http.StatusServiceUnavailable, "Service is not ready.", status)
+		return
+	}
+
+	helper.SendSuccessResponse(c, http.StatusOK, "Service is ready.", status)
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	if err := h.DB.PingContext(ctx); err != nil {
+		return models.HealthCheck{Status: healthStatusDown, Error: err.Error()}
+	}
+	return models.HealthCheck{Status: healthStatusUp, Latency: time.Since(start).String()}
+}
+
+// checkSchema verifies that the tables the service depends on have been created.
+func (h *HealthHandler) checkSchema(ctx context.Context) models.HealthCheck {
+	start := time.Now()
+	var count int
+	query := "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'books'"
+	if err := h.DB.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return models.HealthCheck{Status: healthStatusDown, Error: err.Error()}
+	}
+	if count == 0 {
+		return models.HealthCheck{Status: healthStatusDown, Error: "table books does not exist"}
+	}
+	return models.HealthCheck{Status: healthStatusUp, Latency: time.Since(start).String()}
+}
+
+func (h *HealthHandler) checkDrain() models.HealthCheck {
+	if h.draining.Load() {
+		return models.HealthCheck{Status: healthStatusDown, Error: "server is shutting down"}
+	}
+	return models.HealthCheck{Status: healthStatusUp}
+}
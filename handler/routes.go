@@ -0,0 +1,24 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// BookRoutes is implemented by the book handler of each API version, so a
+// future version with a different response shape can live next to v1.
+type BookRoutes interface {
+	GetAllBooks(c *gin.Context)
+	StreamBooks(c *gin.Context)
+	GetBookByID(c *gin.Context)
+	CreateBook(c *gin.Context)
+	UpdateBook(c *gin.Context)
+	DeleteBook(c *gin.Context)
+}
+
+// RegisterBookRoutes registers the book endpoints on the given route group.
+func RegisterBookRoutes(group *gin.RouterGroup, h BookRoutes) {
+	group.GET("/books", h.GetAllBooks)
+	group.GET("/books/stream", h.StreamBooks)
+	group.GET("/books/:id", h.GetBookByID)
+	group.POST("/books", h.CreateBook)
+	group.PUT("/books/:id", h.UpdateBook)
+	group.DELETE("/books/:id", h.DeleteBook)
+}
@@ -0,0 +1,156 @@
+package helper
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// GetFieldsParam returns the fields listed in the ?fields= query parameter, or
+// in the JSON:API ?fields[type]= parameter of the resource type, which takes
+// precedence. It returns nil when both are absent. A field missing from allowed
+// is answered with 400.
+func GetFieldsParam(c *gin.Context, resourceType string, allowed []string) ([]string, bool) {
+	param, ok := c.GetQuery("fields[" + resourceType + "]")
+	if !ok {
+		param, ok = c.GetQuery("fields")
+	}
+	if !ok {
+		return nil, true
+	}
+
+	var fields []string
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(allowed, field) {
+			SendErrorResponse(c, http.StatusBadRequest, "The fields parameter contains an unknown field.", allowed)
+			return nil, false
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, true
+}
+
+// SelectFields reduces a struct, or a slice of structs, to the fields with the
+// given JSON names. The data is returned unchanged when fields is empty.
+func SelectFields(data any, fields []string) any {
+	if len(fields) == 0 {
+		return data
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(data))
+	switch value.Kind() {
+	case reflect.Struct:
+		return newFieldSet(value, fields)
+	case reflect.Slice:
+		if value.IsNil() {
+			return []fieldSet(nil)
+		}
+		result := make([]fieldSet, value.Len())
+		for i := range result {
+			result[i] = newFieldSet(reflect.Indirect(value.Index(i)), fields)
+		}
+		return result
+	}
+	return data
+}
+
+// fieldSet is a struct reduced to some of its fields. Unlike a map, it keeps
+// the fields in struct order and can be written as XML. The struct is kept as
+// resource, when it is one, so a JSON:API document still has its type and ID.
+type fieldSet struct {
+	names    []string
+	values   []any
+	resource models.Resource
+}
+
+func newFieldSet(value reflect.Value, fields []string) fieldSet {
+	var set fieldSet
+	set.resource, _ = value.Interface().(models.Resource)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if name := jsonFieldName(field); field.IsExported() && slices.Contains(fields, name) {
+			set.names = append(set.names, name)
+			set.values = append(set.values, value.Field(i).Interface())
+		}
+	}
+	return set
+}
+
+// without returns the set without the named field.
+func (s fieldSet) without(name string) fieldSet {
+	result := fieldSet{resource: s.resource}
+	for i, n := range s.names {
+		if n != name {
+			result.names = append(result.names, n)
+			result.values = append(result.values, s.values[i])
+		}
+	}
+	return result
+}
+
+func (s fieldSet) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, name := range s.names {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, _ := JSONCodec.Marshal(name)
+		value, err := JSONCodec.Marshal(s.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func (s fieldSet) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for i, name := range s.names {
+		if err := e.EncodeElement(s.values[i], xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (s fieldSet) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, name := range s.names {
+		var value yaml.Node
+		if err := value.Encode(s.values[i]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, &value)
+	}
+	return node, nil
+}
+
+// CodecEncodeSelf writes the fields as a MessagePack map.
+func (s fieldSet) CodecEncodeSelf(e *codec.Encoder) {
+	values := make(map[string]any, len(s.names))
+	for i, name := range s.names {
+		values[name] = s.values[i]
+	}
+	e.MustEncode(values)
+}
+
+// CodecDecodeSelf is required by codec.Selfer, a fieldSet is only ever encoded.
+func (s *fieldSet) CodecDecodeSelf(*codec.Decoder) {}
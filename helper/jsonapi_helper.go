@@ -0,0 +1,178 @@
+package helper
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MIMEJSONAPI is the media type of JSON:API documents.
+const MIMEJSONAPI = "application/vnd.api+json"
+
+var jsonAPIVersion = models.JSONAPIObject{Version: "1.1"}
+
+// jsonAPIDocument turns the data of a successful response into a JSON:API
+// document. The envelope message goes into meta, together with the total of a list.
+func jsonAPIDocument(c *gin.Context, message string, data any, pagination *models.Pagination) models.JSONAPIDocument {
+	document := models.JSONAPIDocument{
+		JSONAPI: jsonAPIVersion,
+		Data:    jsonAPIData(c, data),
+		Links:   &models.JSONAPILinks{Self: requestURL(c)},
+		Meta:    map[string]any{"message": message},
+	}
+
+	if pagination != nil {
+		document.Meta["total"] = pagination.Total
+		if pagination.Size > 0 {
+			links := document.Links
+			links.First, links.Prev, links.Next, links.Last = pageURLs(c, *pagination)
+		}
+	}
+	return document
+}
+
+// jsonAPIData turns resources, or slices of them, into resource objects. Other data is returned unchanged.
+func jsonAPIData(c *gin.Context, data any) any {
+	switch data := data.(type) {
+	case fieldSet:
+		if data.resource != nil {
+			return jsonAPIResource(c, data.resource, data.without("id"))
+		}
+	case []fieldSet:
+		resources := make([]models.JSONAPIResource, 0, len(data))
+		for _, set := range data {
+			if set.resource == nil {
+				return data
+			}
+			resources = append(resources, jsonAPIResource(c, set.resource, set.without("id")))
+		}
+		return resources
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(data))
+	switch {
+	case !value.IsValid():
+		return data
+	case value.Kind() == reflect.Struct:
+		if resource, ok := value.Interface().(models.Resource); ok {
+			return jsonAPIResource(c, resource, allFields(value).without("id"))
+		}
+	case value.Kind() == reflect.Slice:
+		resources := make([]models.JSONAPIResource, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item := reflect.Indirect(value.Index(i))
+			resource, ok := item.Interface().(models.Resource)
+			if !ok {
+				return data
+			}
+			resources = append(resources, jsonAPIResource(c, resource, allFields(item).without("id")))
+		}
+		return resources
+	}
+	return data
+}
+
+func jsonAPIResource(c *gin.Context, resource models.Resource, attributes fieldSet) models.JSONAPIResource {
+	return models.JSONAPIResource{
+		Type:       resource.ResourceType(),
+		ID:         resource.ResourceID(),
+		Attributes: attributes,
+		Links:      &models.JSONAPILinks{Self: resourceURL(c, resource)},
+	}
+}
+
+// allFields returns a fieldSet holding every field of a struct.
+func allFields(value reflect.Value) fieldSet {
+	var names []string
+	for i := 0; i < value.NumField(); i++ {
+		if name := jsonFieldName(value.Type().Field(i)); value.Type().Field(i).IsExported() && name != "" {
+			names = append(names, name)
+		}
+	}
+	return newFieldSet(value, names)
+}
+
+// JSONAPIDocumentError is returned by BindJSON when a JSON:API request body is
+// not a resource object, or not one of the type and ID the route expects.
+type JSONAPIDocumentError struct {
+	Status  int
+	Message string
+}
+
+func (e *JSONAPIDocumentError) Error() string {
+	return e.Message
+}
+
+// IsJSONAPIRequest reports whether the request body is a JSON:API document.
+func IsJSONAPIRequest(c *gin.Context) bool {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	return err == nil && mediaType == MIMEJSONAPI
+}
+
+// jsonAPIAttributes returns the attributes of the resource object in a JSON:API
+// request body. Its type must be the type of obj and its ID, when given, the
+// :id of the route, otherwise the request is a conflict.
+func jsonAPIAttributes(c *gin.Context, body []byte, obj any) ([]byte, error) {
+	var document struct {
+		Data *struct {
+			Type       string          `json:"type"`
+			ID         *string         `json:"id"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := JSONCodec.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+
+	data := document.Data
+	if data == nil || data.Type == "" || len(data.Attributes) == 0 {
+		return nil, &JSONAPIDocumentError{Status: http.StatusBadRequest, Message: "The request body must be a JSON:API resource object."}
+	}
+	if resource, ok := obj.(models.Resource); ok && data.Type != resource.ResourceType() {
+		return nil, &JSONAPIDocumentError{Status: http.StatusConflict, Message: "The type of the resource does not match the endpoint."}
+	}
+	if id := c.Param("id"); data.ID != nil && id != "" && *data.ID != id {
+		return nil, &JSONAPIDocumentError{Status: http.StatusConflict, Message: "The id of the resource does not match the URL."}
+	}
+	return data.Attributes, nil
+}
+
+// jsonAPIErrors turns an error envelope into a JSON:API error document, with
+// one error object for every validation error. Validation errors of a JSON:API
+// request body point into its data.attributes.
+func jsonAPIErrors(c *gin.Context, envelope models.ResponseError) models.JSONAPIErrorDocument {
+	status := strconv.Itoa(envelope.Code)
+
+	if details, ok := envelope.Errors.([]models.ValidationErrorDetail); ok && len(details) > 0 {
+		pointer := "/"
+		if IsJSONAPIRequest(c) {
+			pointer = "/data/attributes/"
+		}
+		errors := make([]models.JSONAPIError, len(details))
+		for i, detail := range details {
+			errors[i] = models.JSONAPIError{
+				Status: status,
+				Title:  envelope.Message,
+				Detail: detail.Message,
+				Source: &models.JSONAPIErrorSource{Pointer: pointer + strings.ReplaceAll(detail.Field, ".", "/")},
+			}
+		}
+		return models.JSONAPIErrorDocument{JSONAPI: jsonAPIVersion, Errors: errors}
+	}
+
+	apiError := models.JSONAPIError{Status: status, Code: envelope.ErrorCode, Title: envelope.Message}
+	switch errors := envelope.Errors.(type) {
+	case nil:
+	case string:
+		apiError.Detail = errors
+	default:
+		apiError.Meta = map[string]any{"errors": errors}
+	}
+	return models.JSONAPIErrorDocument{JSONAPI: jsonAPIVersion, Errors: []models.JSONAPIError{apiError}}
+}
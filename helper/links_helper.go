@@ -0,0 +1,175 @@
+package helper
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicBaseURL is the scheme and host that links in responses start with,
+// such as https://api.example.com. When empty, they are taken from the request.
+var PublicBaseURL string
+
+// absoluteURL returns the absolute URL of a path with an optional query on this API.
+func absoluteURL(c *gin.Context, path string) string {
+	if PublicBaseURL != "" {
+		return strings.TrimSuffix(PublicBaseURL, "/") + path
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + path
+}
+
+// requestURL returns the absolute URL of the current request.
+func requestURL(c *gin.Context) string {
+	return absoluteURL(c, c.Request.URL.RequestURI())
+}
+
+// resourceURL returns the URL of a resource, which is the request path on
+// routes with an :id and the request path followed by the ID on collections.
+func resourceURL(c *gin.Context, resource models.Resource) string {
+	if c.Param("id") != "" {
+		return absoluteURL(c, c.Request.URL.Path)
+	}
+	return absoluteURL(c, strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+url.PathEscape(resource.ResourceID()))
+}
+
+// collectionURL returns the URL of the collection the requested resource belongs to.
+func collectionURL(c *gin.Context) string {
+	path := strings.TrimSuffix(c.Request.URL.Path, "/")
+	if c.Param("id") != "" {
+		path = path[:strings.LastIndex(path, "/")]
+	}
+	return absoluteURL(c, path)
+}
+
+// pageURL returns the request URL moved to another page, keeping the other query parameters.
+func pageURL(c *gin.Context, number, size int) string {
+	query := c.Request.URL.Query()
+	query.Set("page[number]", strconv.Itoa(number))
+	query.Set("page[size]", strconv.Itoa(size))
+	// Brackets are allowed in a query string, and keeping them makes the links readable
+	return absoluteURL(c, c.Request.URL.Path+"?"+strings.NewReplacer("%5B", "[", "%5D", "]").Replace(query.Encode()))
+}
+
+// pageURLs returns the URLs of the first, previous, next and last pages of a
+// paginated list, leaving out those that do not exist.
+func pageURLs(c *gin.Context, pagination models.Pagination) (first, prev, next, last string) {
+	lastPage := pagination.LastPage()
+	first = pageURL(c, 1, pagination.Size)
+	last = pageURL(c, lastPage, pagination.Size)
+	if pagination.Number > 1 {
+		prev = pageURL(c, min(pagination.Number-1, lastPage), pagination.Size)
+	}
+	if pagination.Number < lastPage {
+		next = pageURL(c, pagination.Number+1, pagination.Size)
+	}
+	return first, prev, next, last
+}
+
+// halLinks returns the HAL links of a list response, or nil for other responses.
+func halLinks(c *gin.Context, pagination *models.Pagination) *models.HALLinks {
+	if pagination == nil {
+		return nil
+	}
+
+	links := &models.HALLinks{Self: models.NewLink(requestURL(c))}
+	if pagination.Size > 0 {
+		first, prev, next, last := pageURLs(c, *pagination)
+		links.First, links.Prev, links.Next, links.Last = models.NewLink(first), models.NewLink(prev), models.NewLink(next), models.NewLink(last)
+	}
+	return links
+}
+
+// halData adds HAL links to resources, or to every resource in a slice. Other data is returned unchanged.
+func halData(c *gin.Context, data any) any {
+	switch data := data.(type) {
+	case fieldSet:
+		if data.resource != nil {
+			return withHALLinks(c, data)
+		}
+		return data
+	case []fieldSet:
+		result := make([]fieldSet, len(data))
+		for i, set := range data {
+			if set.resource == nil {
+				return data
+			}
+			result[i] = withHALLinks(c, set)
+		}
+		return result
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(data))
+	switch {
+	case !value.IsValid():
+		return data
+	case value.Kind() == reflect.Struct:
+		if resource, ok := value.Interface().(models.Resource); ok {
+			return linkedResource(c, value, resource).Interface()
+		}
+	case value.Kind() == reflect.Slice:
+		if value.IsNil() {
+			return data
+		}
+		elemType := value.Type().Elem()
+		if !elemType.Implements(reflect.TypeOf((*models.Resource)(nil)).Elem()) || elemType.Kind() != reflect.Struct {
+			return data
+		}
+		result := reflect.MakeSlice(reflect.SliceOf(linkedType(elemType)), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			result.Index(i).Set(linkedResource(c, item, item.Interface().(models.Resource)))
+		}
+		return result.Interface()
+	}
+	return data
+}
+
+// linkedTypes caches the struct types made by linkedType.
+var linkedTypes sync.Map
+
+// linkedType returns a struct type that embeds a resource type and adds its
+// HAL links. Unlike a fieldSet, it is encoded as fast as the resource itself.
+func linkedType(resourceType reflect.Type) reflect.Type {
+	if t, ok := linkedTypes.Load(resourceType); ok {
+		return t.(reflect.Type)
+	}
+	t := reflect.StructOf([]reflect.StructField{
+		{Name: resourceType.Name(), Type: resourceType, Anonymous: true, Tag: `yaml:",inline"`},
+		{Name: "Links", Type: reflect.TypeOf(&models.HALLinks{}), Tag: `json:"_links" xml:"_links" yaml:"_links"`},
+	})
+	linkedTypes.Store(resourceType, t)
+	return t
+}
+
+func linkedResource(c *gin.Context, value reflect.Value, resource models.Resource) reflect.Value {
+	linked := reflect.New(linkedType(value.Type())).Elem()
+	linked.Field(0).Set(value)
+	linked.Field(1).Set(reflect.ValueOf(resourceLinks(c, resource)))
+	return linked
+}
+
+func resourceLinks(c *gin.Context, resource models.Resource) *models.HALLinks {
+	return &models.HALLinks{
+		Self:       models.NewLink(resourceURL(c, resource)),
+		Collection: models.NewLink(collectionURL(c)),
+	}
+}
+
+func withHALLinks(c *gin.Context, set fieldSet) fieldSet {
+	links := resourceLinks(c, set.resource)
+	return fieldSet{
+		names:    append(set.names[:len(set.names):len(set.names)], "_links"),
+		values:   append(set.values[:len(set.values):len(set.values)], links),
+		resource: set.resource,
+	}
+}
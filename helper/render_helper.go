@@ -0,0 +1,280 @@
+package helper
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/jsoncodec"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// JSONCodec is the JSON library used for responses and request bodies.
+var JSONCodec = jsoncodec.Std
+
+// MIMECSV is the media type of CSV responses, offered for list endpoints only.
+const MIMECSV = "text/csv"
+
+// EnvelopeFormats are the formats every response can be sent in, in order of preference.
+// JSON:API documents carry the envelope message and errors in their own structure.
+var EnvelopeFormats = []string{
+	binding.MIMEJSON,
+	binding.MIMEXML,
+	binding.MIMEXML2,
+	binding.MIMEYAML,
+	binding.MIMEYAML2,
+	binding.MIMEMSGPACK,
+	binding.MIMEMSGPACK2,
+	MIMEJSONAPI,
+}
+
+// negotiateFormat returns the offered format the client prefers, honouring
+// the q-values of the Accept header, or "" when none is acceptable.
+func negotiateFormat(c *gin.Context, offered ...string) string {
+	if c.Accepted == nil {
+		c.SetAccepted(acceptedFormats(c.GetHeader("Accept"))...)
+	}
+	return c.NegotiateFormat(offered...)
+}
+
+// acceptedFormats parses an Accept header into media types ordered by q-value, dropping those with q=0.
+func acceptedFormats(header string) []string {
+	type accepted struct {
+		format  string
+		quality float64
+	}
+
+	var formats []accepted
+	for _, part := range strings.Split(header, ",") {
+		format, params, _ := strings.Cut(part, ";")
+		if format = strings.TrimSpace(format); format == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if quality > 0 {
+			formats = append(formats, accepted{format: format, quality: quality})
+		}
+	}
+	sort.SliceStable(formats, func(i, j int) bool { return formats[i].quality > formats[j].quality })
+
+	result := make([]string, len(formats))
+	for i, f := range formats {
+		result[i] = f.format
+	}
+	return result
+}
+
+// renderEnvelope writes the envelope in the given format.
+func renderEnvelope(c *gin.Context, format string, statusCode int, envelope any) {
+	switch format {
+	case binding.MIMEXML, binding.MIMEXML2:
+		c.XML(statusCode, envelope)
+	case binding.MIMEYAML, binding.MIMEYAML2:
+		c.YAML(statusCode, envelope)
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Render(statusCode, render.MsgPack{Data: envelope})
+	default:
+		if list, ok := envelope.(models.ResponseSuccess); ok && isList(list.Data) {
+			c.Render(statusCode, jsonListRender{envelope: list})
+			return
+		}
+		c.Render(statusCode, jsonRender{contentType: binding.MIMEJSON, data: envelope})
+	}
+}
+
+// renderJSONAPI writes a JSON:API document with its own media type.
+func renderJSONAPI(c *gin.Context, statusCode int, document any) {
+	c.Render(statusCode, jsonRender{contentType: MIMEJSONAPI, data: document})
+}
+
+// jsonRender writes data with JSONCodec.
+type jsonRender struct {
+	contentType string
+	data        any
+}
+
+func (r jsonRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return JSONCodec.NewEncoder(w).Encode(r.data)
+}
+
+func (r jsonRender) WriteContentType(w http.ResponseWriter) {
+	if r.contentType == binding.MIMEJSON {
+		w.Header().Set("Content-Type", binding.MIMEJSON+"; charset=utf-8")
+		return
+	}
+	w.Header().Set("Content-Type", r.contentType)
+}
+
+// jsonListRender writes an envelope holding a list one item at a time, so the
+// encoded list is never held in memory as a whole. The fields are written in
+// the order of models.ResponseSuccess.
+type jsonListRender struct {
+	envelope models.ResponseSuccess
+}
+
+func (r jsonListRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	message, err := JSONCodec.Marshal(r.envelope.Message)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"code":%d,"message":%s,"data":`, r.envelope.Code, message); err != nil {
+		return err
+	}
+
+	items := reflect.ValueOf(r.envelope.Data)
+	if items.IsNil() {
+		io.WriteString(w, "null")
+	} else {
+		io.WriteString(w, "[")
+		for i := 0; i < items.Len(); i++ {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			if err := writeJSON(w, items.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		io.WriteString(w, "]")
+	}
+
+	if r.envelope.Links != nil {
+		io.WriteString(w, `,"_links":`)
+		if err := writeJSON(w, r.envelope.Links); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "}")
+	return err
+}
+
+func (r jsonListRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", binding.MIMEJSON+"; charset=utf-8")
+}
+
+// writeJSON writes a single value without the newline an Encoder would add.
+func writeJSON(w io.Writer, v any) error {
+	data, err := JSONCodec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// isList reports whether data is a slice, which is streamed item by item.
+func isList(data any) bool {
+	return data != nil && reflect.TypeOf(data).Kind() == reflect.Slice
+}
+
+// renderCSV writes a slice of structs as CSV, using the JSON field names as the header row.
+func renderCSV(c *gin.Context, statusCode int, data any) error {
+	if sets, ok := data.([]fieldSet); ok {
+		return renderFieldSetsCSV(c, statusCode, sets)
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(data))
+	elemType := rows.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	var fields []int
+	var header []string
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, i)
+		header = append(header, name)
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write(header)
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		record := make([]string, len(fields))
+		for j, field := range fields {
+			record[j] = csvValue(row.Field(field))
+		}
+		writer.Write(record)
+	}
+	return writeCSV(c, statusCode, writer, &buffer)
+}
+
+// renderFieldSetsCSV writes the rows of a sparse fieldset, whose header row is
+// taken from the first row as every row holds the same fields.
+func renderFieldSetsCSV(c *gin.Context, statusCode int, sets []fieldSet) error {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	for i, set := range sets {
+		if i == 0 {
+			writer.Write(set.names)
+		}
+		record := make([]string, len(set.values))
+		for j, value := range set.values {
+			record[j] = csvValue(reflect.ValueOf(value))
+		}
+		writer.Write(record)
+	}
+	return writeCSV(c, statusCode, writer, &buffer)
+}
+
+func writeCSV(c *gin.Context, statusCode int, writer *csv.Writer, buffer *bytes.Buffer) error {
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+
+	c.Data(statusCode, MIMECSV+"; charset=utf-8", buffer.Bytes())
+	return nil
+}
+
+func csvValue(value reflect.Value) string {
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// isStructSlice reports whether data is a slice of structs that can be written as CSV rows.
+func isStructSlice(data any) bool {
+	if _, ok := data.([]fieldSet); ok {
+		return true
+	}
+	value := reflect.Indirect(reflect.ValueOf(data))
+	if value.Kind() != reflect.Slice {
+		return false
+	}
+	elemType := value.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	return elemType.Kind() == reflect.Struct && elemType != reflect.TypeOf(time.Time{})
+}
@@ -0,0 +1,229 @@
+package helper
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/rs/zerolog"
+)
+
+// GetIDParam returns the numeric :id path parameter. Requests are validated
+// against the OpenAPI document before they reach a handler, so the check here
+// only guards routers registered without that middleware.
+func GetIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("[Helper] Failed to convert ID from URL.")
+		SendErrorResponse(c, http.StatusBadRequest, "ID must be a valid number.", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// DefaultPageSize is the page size used when only page[number] is given, and MaxPageSize the largest allowed.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// GetPageParams returns the page selected with the page[number] and page[size]
+// query parameters. Without either of them the whole list is selected.
+func GetPageParams(c *gin.Context) (models.Page, bool) {
+	number, hasNumber := c.GetQuery("page[number]")
+	size, hasSize := c.GetQuery("page[size]")
+	if !hasNumber && !hasSize {
+		return models.Page{}, true
+	}
+
+	page := models.Page{Number: 1, Size: DefaultPageSize}
+	if hasNumber {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 1 {
+			SendErrorResponse(c, http.StatusBadRequest, "page[number] must be a positive number.", nil)
+			return models.Page{}, false
+		}
+		page.Number = n
+	}
+	if hasSize {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 1 || n > MaxPageSize {
+			SendErrorResponse(c, http.StatusBadRequest, "page[size] must be between 1 and 100.", nil)
+			return models.Page{}, false
+		}
+		page.Size = n
+	}
+	return page, true
+}
+
+// DuplicateKeyError is returned by BindJSON when an object in the body repeats a key.
+type DuplicateKeyError struct {
+	Field string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("json: duplicate field %q", e.Field)
+}
+
+// UnknownFieldError is returned by BindJSON when the body has a field the target does not.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("json: unknown field %q", e.Field)
+}
+
+// BindJSON decodes the JSON body with JSONCodec and validates it like c.ShouldBindJSON. When
+// binding.EnableDecoderDisallowUnknownFields is set, decoding is strict and
+// unknown fields and duplicate keys are rejected as well. They are found by
+// walking the body before decoding, so they are reported the same way whatever
+// JSONCodec is. A JSON:API document is decoded from the attributes of its
+// resource object.
+func BindJSON(c *gin.Context, obj any) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	if IsJSONAPIRequest(c) {
+		if body, err = jsonAPIAttributes(c, body, obj); err != nil {
+			return err
+		}
+	}
+
+	if binding.EnableDecoderDisallowUnknownFields {
+		if err := checkKeys(json.NewDecoder(bytes.NewReader(body)), reflect.TypeOf(obj), ""); err != nil {
+			return err
+		}
+	}
+
+	decoder := JSONCodec.NewDecoder(bytes.NewReader(body))
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// checkKeys walks the next JSON value and reports the first repeated object
+// key, or the first key that target, the type it is decoded into, has no field
+// for. Keys of structs are matched like encoding/json does, ignoring case,
+// while keys of maps must be repeated exactly to be duplicates. Values decoded
+// into anything but structs, maps and slices are only checked for duplicates.
+// Syntax errors are left for the decoder to report.
+func checkKeys(decoder *json.Decoder, target reflect.Type, path string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil
+	}
+
+	for target != nil && target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+	if target != nil && (reflect.PointerTo(target).Implements(jsonUnmarshalerType) || target.Implements(jsonUnmarshalerType)) {
+		target = nil
+	}
+
+	switch token {
+	case json.Delim('{'):
+		var fields map[string]reflect.Type
+		var elem reflect.Type
+		if target != nil {
+			switch target.Kind() {
+			case reflect.Struct:
+				fields = jsonFields(target)
+			case reflect.Map:
+				elem = target.Elem()
+			}
+		}
+
+		seen := map[string]bool{}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil
+			}
+			key, _ := keyToken.(string)
+			// Keys that differ only in case fill the same struct field, so they are duplicates too
+			name := key
+			if fields != nil {
+				name = strings.ToLower(key)
+			}
+			if seen[name] {
+				return &DuplicateKeyError{Field: path + key}
+			}
+			seen[name] = true
+
+			fieldType := elem
+			if fields != nil {
+				var known bool
+				if fieldType, known = fields[name]; !known {
+					return &UnknownFieldError{Field: path + key}
+				}
+			}
+			if err := checkKeys(decoder, fieldType, path+key+"."); err != nil {
+				return err
+			}
+		}
+		decoder.Token()
+	case json.Delim('['):
+		var elem reflect.Type
+		if target != nil && (target.Kind() == reflect.Slice || target.Kind() == reflect.Array) {
+			elem = target.Elem()
+		}
+		for decoder.More() {
+			if err := checkKeys(decoder, elem, path); err != nil {
+				return err
+			}
+		}
+		decoder.Token()
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonFields returns the types of the fields of a struct by their lower-cased
+// JSON names, including the fields promoted from embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for embedded, embeddedType := range jsonFields(fieldType) {
+				if _, ok := fields[embedded]; !ok {
+					fields[embedded] = embeddedType
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
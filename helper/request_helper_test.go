@@ -0,0 +1,90 @@
+package helper
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/jsoncodec"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+// nestedInput covers the shapes the key walk descends into.
+type nestedInput struct {
+	models.Book
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Editors []struct {
+		Name string `json:"name"`
+	} `json:"editors"`
+	Ignored string `json:"-"`
+}
+
+func TestBindJSONStrict(t *testing.T) {
+	previousStrict, previousCodec := binding.EnableDecoderDisallowUnknownFields, JSONCodec
+	binding.EnableDecoderDisallowUnknownFields = true
+	defer func() { binding.EnableDecoderDisallowUnknownFields, JSONCodec = previousStrict, previousCodec }()
+
+	tests := []struct {
+		name      string
+		body      string
+		wantErr   error
+		wantField string
+	}{
+		{name: "Known Fields", body: `{"title":"Go","author":"Rob","year":2015}`},
+		{name: "Field Names Ignore Case", body: `{"Title":"Go","AUTHOR":"Rob","year":2015}`},
+		{name: "Time Values Are Not Walked", body: `{"title":"Go","author":"Rob","year":2015,"created_at":"2024-01-01T00:00:00Z"}`},
+		{name: "Nested Known Fields", body: `{"title":"Go","author":"Rob","year":2015,"tags":["a"],"labels":{"any":"x"},"editors":[{"name":"Ann"}]}`},
+		{name: "Unknown Field", body: `{"title":"Go","author":"Rob","year":2015,"isbn":"1"}`, wantErr: &UnknownFieldError{}, wantField: "isbn"},
+		{name: "Field Excluded With A Dash", body: `{"title":"Go","author":"Rob","year":2015,"Ignored":"x"}`, wantErr: &UnknownFieldError{}, wantField: "Ignored"},
+		{name: "Unknown Nested Field", body: `{"title":"Go","author":"Rob","year":2015,"editors":[{"name":"Ann","age":3}]}`, wantErr: &UnknownFieldError{}, wantField: "editors.age"},
+		{name: "Duplicate Field", body: `{"title":"Go","title":"Rust","author":"Rob","year":2015}`, wantErr: &DuplicateKeyError{}, wantField: "title"},
+		{name: "Duplicate Field In Another Case", body: `{"title":"Go","Title":"Rust","author":"Rob","year":2015}`, wantErr: &DuplicateKeyError{}, wantField: "Title"},
+		{name: "Duplicate Nested Field In Another Case", body: `{"title":"Go","author":"Rob","year":2015,"editors":[{"name":"Ann","NAME":"Bob"}]}`, wantErr: &DuplicateKeyError{}, wantField: "editors.NAME"},
+		{name: "Map Keys Differing In Case", body: `{"title":"Go","author":"Rob","year":2015,"labels":{"a":"x","A":"y"}}`},
+		{name: "Duplicate Map Key", body: `{"title":"Go","author":"Rob","year":2015,"labels":{"a":"x","a":"y"}}`, wantErr: &DuplicateKeyError{}, wantField: "labels.a"},
+	}
+
+	for _, codec := range []jsoncodec.Codec{jsoncodec.Std, jsoncodec.Sonic, jsoncodec.GoJSON} {
+		JSONCodec = codec
+		for _, tt := range tests {
+			// Define test for case tt.name with each codec
+			t.Run(codec.Name+"/"+tt.name, func(t *testing.T) {
+				c, _ := gin.CreateTestContext(httptest.NewRecorder())
+				c.Request = httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(tt.body))
+				c.Request.Header.Set("Content-Type", binding.MIMEJSON)
+
+				err := BindJSON(c, &nestedInput{})
+				switch want := tt.wantErr.(type) {
+				case nil:
+					assert.NoError(t, err)
+				case *UnknownFieldError:
+					if assert.ErrorAs(t, err, &want) {
+						assert.Equal(t, tt.wantField, want.Field)
+					}
+				case *DuplicateKeyError:
+					if assert.ErrorAs(t, err, &want) {
+						assert.Equal(t, tt.wantField, want.Field)
+					}
+				}
+			})
+		}
+	}
+
+	// Define test for case Unknown Field Response
+	t.Run("Unknown Field Response", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/books", nil)
+
+		HandleValidationError(c, &UnknownFieldError{Field: "isbn"})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"isbn"`)
+		assert.Contains(t, w.Body.String(), `This field is not allowed`)
+	})
+}
@@ -1,35 +1,107 @@
 package helper
 
 import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/i18n"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/rs/zerolog"
 )
 
-// SendSuccessResponse sends a successful response.
+// SendSuccessResponse sends a successful response in the format negotiated from the
+// Accept header, translating the message for the request locale. Lists can also be sent as CSV.
+// Resources such as books get HAL links to themselves and their collection.
 func SendSuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
-	c.JSON(statusCode, models.ResponseSuccess{
-		Code:    statusCode,
-		Message: message,
-		Data:    data,
-	})
+	sendSuccess(c, statusCode, message, data, nil)
+}
+
+// SendListResponse sends a list like SendSuccessResponse, adding links to the
+// list and its other pages, and the total to JSON:API documents.
+func SendListResponse(c *gin.Context, statusCode int, message string, data interface{}, pagination models.Pagination) {
+	sendSuccess(c, statusCode, message, data, &pagination)
+}
+
+func sendSuccess(c *gin.Context, statusCode int, message string, data interface{}, pagination *models.Pagination) {
+	offered := EnvelopeFormats
+	if isStructSlice(data) {
+		offered = append(offered[:len(offered):len(offered)], MIMECSV)
+	}
+
+	format := negotiateFormat(c, offered...)
+	switch format {
+	case "":
+		sendNotAcceptable(c, offered)
+	case MIMECSV:
+		// CSV has no room for the envelope, so only the rows are sent
+		if err := renderCSV(c, statusCode, data); err != nil {
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("[Helper] Failed to write CSV response")
+			SendErrorResponse(c, http.StatusInternalServerError, "Internal server error.", nil)
+		}
+	case MIMEJSONAPI:
+		renderJSONAPI(c, statusCode, jsonAPIDocument(c, i18n.T(c, message), data, pagination))
+	default:
+		renderEnvelope(c, format, statusCode, models.ResponseSuccess{
+			Code:    statusCode,
+			Message: i18n.T(c, message),
+			Data:    halData(c, data),
+			Links:   halLinks(c, pagination),
+		})
+	}
 }
 
-// SendErrorResponse sends an error response with an error message.
+// SendErrorResponse sends an error response with an error message, translated for the request locale.
 func SendErrorResponse(c *gin.Context, statusCode int, message string, errors interface{}) {
-	c.JSON(statusCode, models.ResponseError{
+	sendError(c, models.ResponseError{
 		Code:    statusCode,
-		Message: message,
+		Message: i18n.T(c, message),
 		Errors:  errors,
 	})
 }
 
 // SendValidationError Response sends validation error response.
 func SendValidationErrorResponse(c *gin.Context, errors []models.ValidationErrorDetail) {
-	c.JSON(http.StatusUnprocessableEntity, models.ResponseError{
+	sendError(c, models.ResponseError{
 		Code:    http.StatusUnprocessableEntity,
-		Message: "validation error",
+		Message: i18n.T(c, "validation.error.message"),
 		Errors:  errors,
 	})
 }
+
+// ErrorCodeRequestTimeout marks the errors of requests that ran past their deadline.
+const ErrorCodeRequestTimeout = "request_timeout"
+
+// sendError writes the error envelope in the negotiated format, falling back to JSON
+// so that errors are never hidden behind a 406. Server errors of a request past its
+// deadline are caused by the cancelled query, so they are sent as a 504 instead.
+func sendError(c *gin.Context, envelope models.ResponseError) {
+	if envelope.Code >= http.StatusInternalServerError && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		envelope = models.ResponseError{
+			Code:      http.StatusGatewayTimeout,
+			ErrorCode: ErrorCodeRequestTimeout,
+			Message:   i18n.T(c, "The request took too long and was cancelled."),
+		}
+	}
+
+	format := negotiateFormat(c, EnvelopeFormats...)
+	switch format {
+	case "":
+		renderEnvelope(c, binding.MIMEJSON, envelope.Code, envelope)
+	case MIMEJSONAPI:
+		renderJSONAPI(c, envelope.Code, jsonAPIErrors(c, envelope))
+	default:
+		renderEnvelope(c, format, envelope.Code, envelope)
+	}
+}
+
+// sendNotAcceptable responds with 406, listing the supported formats.
+func sendNotAcceptable(c *gin.Context, offered []string) {
+	renderEnvelope(c, binding.MIMEJSON, http.StatusNotAcceptable, models.ResponseError{
+		Code:    http.StatusNotAcceptable,
+		Message: i18n.T(c, "None of the accepted formats is supported."),
+		Errors:  offered,
+	})
+}
@@ -0,0 +1,122 @@
+package helper
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// MIMENDJSON is the media type of newline delimited JSON, one value per line.
+const MIMENDJSON = "application/x-ndjson"
+
+// streamFlushEvery is the number of items written between flushes, so the client
+// receives a stream as it is read without a flush for every item.
+const streamFlushEvery = 100
+
+// StreamWriteTimeout is how long writing may take between two flushes of a
+// stream. Streams are not bound by the server write timeout.
+var StreamWriteTimeout = 30 * time.Second
+
+// ListStream writes a list to the client one item at a time, as a JSON array
+// or as NDJSON, as negotiated from the Accept header.
+type ListStream struct {
+	c       *gin.Context
+	format  string
+	count   int
+	started bool
+}
+
+// NewListStream negotiates the format of a stream and responds with 406 when
+// neither a JSON array nor NDJSON is acceptable.
+func NewListStream(c *gin.Context) (*ListStream, bool) {
+	offered := []string{binding.MIMEJSON, MIMENDJSON}
+	format := negotiateFormat(c, offered...)
+	if format == "" {
+		sendNotAcceptable(c, offered)
+		return nil, false
+	}
+	return &ListStream{c: c, format: format}, true
+}
+
+// Write writes one item, sending the headers first. Writing fails once the client has gone.
+func (s *ListStream) Write(item any) error {
+	w := s.c.Writer
+	if !s.started {
+		s.start()
+	}
+
+	if s.format == MIMENDJSON {
+		if err := writeJSON(w, item); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	} else {
+		if s.count > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := writeJSON(w, item); err != nil {
+			return err
+		}
+	}
+
+	s.count++
+	if s.count%streamFlushEvery == 0 {
+		s.flush()
+	}
+	return nil
+}
+
+// Started reports whether the headers have been sent, after which an error can no longer be responded with.
+func (s *ListStream) Started() bool {
+	return s.started
+}
+
+// Close finishes the stream, sending an empty list when nothing was written.
+func (s *ListStream) Close() error {
+	if !s.started {
+		s.start()
+	}
+	if s.format != MIMENDJSON {
+		if _, err := io.WriteString(s.c.Writer, "]"); err != nil {
+			return err
+		}
+	}
+	s.flush()
+	return nil
+}
+
+// Abort drops the connection of a stream that failed after it started, so the
+// client sees an incomplete response instead of a list that looks complete.
+func (s *ListStream) Abort() {
+	panic(http.ErrAbortHandler)
+}
+
+func (s *ListStream) start() {
+	s.started = true
+	s.c.Header("Content-Type", s.format)
+	if s.format == binding.MIMEJSON {
+		s.c.Header("Content-Type", binding.MIMEJSON+"; charset=utf-8")
+	}
+	s.c.Status(http.StatusOK)
+	s.extendWriteDeadline()
+	if s.format != MIMENDJSON {
+		io.WriteString(s.c.Writer, "[")
+	}
+}
+
+func (s *ListStream) flush() {
+	s.c.Writer.Flush()
+	s.extendWriteDeadline()
+}
+
+func (s *ListStream) extendWriteDeadline() {
+	// Not every writer supports deadlines, e.g. the recorder in tests
+	http.NewResponseController(s.c.Writer).SetWriteDeadline(time.Now().Add(StreamWriteTimeout))
+}
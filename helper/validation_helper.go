@@ -1,35 +1,129 @@
 package helper
 
 import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/i18n"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
 	"errors"
 	"net/http"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		RegisterValidators(v)
+	}
+}
+
+// RegisterValidators reports JSON field names in validation errors and registers the custom validators.
+func RegisterValidators(v *validator.Validate) {
+	v.RegisterTagNameFunc(jsonFieldName)
+	v.RegisterValidation("notfuture", notFuture)
+}
+
+// jsonFieldName returns the name of the field in the JSON body, falling back to the Go field name.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// notFuture validates that a year (int) or a time.Time is not in the future.
+func notFuture(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() <= int64(time.Now().Year())
+	case reflect.Struct:
+		if t, ok := field.Interface().(time.Time); ok {
+			return !t.After(time.Now())
+		}
+	}
+	return false
+}
+
 // HandleValidationError process validation errors and send appropriate responses.
 func HandleValidationError(c *gin.Context, err error) {
 	var ve validator.ValidationErrors
-	if errors.As(err, &ve) {
+	var maxBytesErr *http.MaxBytesError
+	var duplicateErr *DuplicateKeyError
+	var unknownErr *UnknownFieldError
+	var documentErr *JSONAPIDocumentError
+
+	switch {
+	case errors.As(err, &ve):
 		validationErrors := make([]models.ValidationErrorDetail, len(ve))
 		for i, fieldError := range ve {
 			validationErrors[i] = models.ValidationErrorDetail{
 				Field:   fieldError.Field(),
-				Message: msgForTag(fieldError.Tag()),
+				Message: msgForTag(c, fieldError),
 			}
 		}
 		SendValidationErrorResponse(c, validationErrors)
-	} else {
+	case errors.As(err, &maxBytesErr):
+		SendErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body is too large.", nil)
+	case errors.As(err, &documentErr):
+		SendErrorResponse(c, documentErr.Status, documentErr.Message, nil)
+	case errors.As(err, &duplicateErr):
+		SendValidationErrorResponse(c, []models.ValidationErrorDetail{{
+			Field:   duplicateErr.Field,
+			Message: i18n.T(c, "validation.duplicate"),
+		}})
+	case errors.As(err, &unknownErr):
+		SendValidationErrorResponse(c, []models.ValidationErrorDetail{{
+			Field:   unknownErr.Field,
+			Message: i18n.T(c, "validation.unknown"),
+		}})
+	default:
 		SendErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
 	}
 }
 
-func msgForTag(tag string) string {
-	switch tag {
-	case "required":
-		return "This field is required"
+func msgForTag(c *gin.Context, fe validator.FieldError) string {
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required", "notfuture", "email", "uuid", "alpha", "alphanum", "boolean":
+		return i18n.T(c, "validation."+fe.Tag())
+	case "min", "gte":
+		return i18n.T(c, withUnit(fe, "validation.min"), param)
+	case "max", "lte":
+		return i18n.T(c, withUnit(fe, "validation.max"), param)
+	case "gt", "lt", "len":
+		return i18n.T(c, withUnit(fe, "validation."+fe.Tag()), param)
+	case "eq", "ne", "datetime":
+		return i18n.T(c, "validation."+fe.Tag(), param)
+	case "oneof":
+		return i18n.T(c, "validation.oneof", strings.Join(strings.Fields(param), ", "))
+	case "url", "http_url":
+		return i18n.T(c, "validation.url")
+	case "uuid4":
+		return i18n.T(c, "validation.uuid")
+	case "numeric", "number":
+		return i18n.T(c, "validation.numeric")
+	case "isbn", "isbn10", "isbn13":
+		return i18n.T(c, "validation.isbn")
+	}
+	return i18n.T(c, "validation.default", fe.Tag())
+}
+
+// withUnit picks the message variant matching the field kind, e.g. "validation.min.string" for a text length.
+func withUnit(fe validator.FieldError, key string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return key + ".string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return key + ".items"
 	}
-	return ""
+	return key
 }
@@ -0,0 +1,109 @@
+package i18n
+
+// catalogues holds the translated messages per locale. Fixed API messages are
+// keyed by their English text, so English needs no entry for them; validation
+// messages use "validation." keys with {0} as the placeholder for the tag parameter.
+var catalogues = map[string]map[string]string{
+	"en": {
+		"validation.required":      "This field is required",
+		"validation.min":           "Must be at least {0}",
+		"validation.min.string":    "Must be at least {0} characters long",
+		"validation.min.items":     "Must contain at least {0} items",
+		"validation.max":           "Must be at most {0}",
+		"validation.max.string":    "Must be at most {0} characters long",
+		"validation.max.items":     "Must contain at most {0} items",
+		"validation.gt":            "Must be greater than {0}",
+		"validation.gt.string":     "Must be longer than {0} characters",
+		"validation.gt.items":      "Must contain more than {0} items",
+		"validation.lt":            "Must be less than {0}",
+		"validation.lt.string":     "Must be shorter than {0} characters",
+		"validation.lt.items":      "Must contain less than {0} items",
+		"validation.len":           "Must be exactly {0}",
+		"validation.len.string":    "Must be exactly {0} characters long",
+		"validation.len.items":     "Must contain exactly {0} items",
+		"validation.eq":            "Must be equal to {0}",
+		"validation.ne":            "Must not be equal to {0}",
+		"validation.oneof":         "Must be one of: {0}",
+		"validation.email":         "Must be a valid email address",
+		"validation.url":           "Must be a valid URL",
+		"validation.uuid":          "Must be a valid UUID",
+		"validation.alpha":         "Must contain only letters",
+		"validation.alphanum":      "Must contain only letters and numbers",
+		"validation.numeric":       "Must be a number",
+		"validation.boolean":       "Must be a boolean",
+		"validation.datetime":      "Must be a date in the format {0}",
+		"validation.isbn":          "Must be a valid ISBN",
+		"validation.notfuture":     "Must not be in the future",
+		"validation.default":       "Failed the {0} validation",
+		"validation.empty":         "This field must not be empty",
+		"validation.unknown":       "This field is not allowed",
+		"validation.duplicate":     "This field appears more than once",
+		"validation.error.message": "validation error",
+	},
+	"id": {
+		"validation.required":      "Kolom ini wajib diisi",
+		"validation.min":           "Minimal {0}",
+		"validation.min.string":    "Minimal {0} karakter",
+		"validation.min.items":     "Minimal berisi {0} item",
+		"validation.max":           "Maksimal {0}",
+		"validation.max.string":    "Maksimal {0} karakter",
+		"validation.max.items":     "Maksimal berisi {0} item",
+		"validation.gt":            "Harus lebih besar dari {0}",
+		"validation.gt.string":     "Harus lebih dari {0} karakter",
+		"validation.gt.items":      "Harus berisi lebih dari {0} item",
+		"validation.lt":            "Harus lebih kecil dari {0}",
+		"validation.lt.string":     "Harus kurang dari {0} karakter",
+		"validation.lt.items":      "Harus berisi kurang dari {0} item",
+		"validation.len":           "Harus tepat {0}",
+		"validation.len.string":    "Harus tepat {0} karakter",
+		"validation.len.items":     "Harus berisi tepat {0} item",
+		"validation.eq":            "Harus sama dengan {0}",
+		"validation.ne":            "Tidak boleh sama dengan {0}",
+		"validation.oneof":         "Harus salah satu dari: {0}",
+		"validation.email":         "Harus berupa alamat email yang valid",
+		"validation.url":           "Harus berupa URL yang valid",
+		"validation.uuid":          "Harus berupa UUID yang valid",
+		"validation.alpha":         "Hanya boleh berisi huruf",
+		"validation.alphanum":      "Hanya boleh berisi huruf dan angka",
+		"validation.numeric":       "Harus berupa angka",
+		"validation.boolean":       "Harus berupa boolean",
+		"validation.datetime":      "Harus berupa tanggal dengan format {0}",
+		"validation.isbn":          "Harus berupa ISBN yang valid",
+		"validation.notfuture":     "Tidak boleh di masa depan",
+		"validation.default":       "Gagal pada validasi {0}",
+		"validation.empty":         "Kolom ini tidak boleh kosong",
+		"validation.unknown":       "Kolom ini tidak diizinkan",
+		"validation.duplicate":     "Kolom ini muncul lebih dari sekali",
+		"validation.error.message": "kesalahan validasi",
+
+		"Successfully got all data.":                              "Berhasil mendapatkan semua data.",
+		"Successfully got the data.":                              "Berhasil mendapatkan data.",
+		"Successfully created data":                               "Berhasil membuat data",
+		"Successfully updated data.":                              "Berhasil memperbarui data.",
+		"Successfully deleted data.":                              "Berhasil menghapus data.",
+		"Failed to get data":                                      "Gagal mendapatkan data",
+		"Failed to delete data.":                                  "Gagal menghapus data.",
+		"ID must be a valid number.":                              "ID harus berupa angka yang valid.",
+		"Data with that ID does not exist.":                       "Data dengan ID tersebut tidak ada.",
+		"Data with that ID does not exist, cannot update.":        "Data dengan ID tersebut tidak ada, tidak dapat diperbarui.",
+		"Data with that ID does not exist, you cannot delete it.": "Data dengan ID tersebut tidak ada, Anda tidak dapat menghapusnya.",
+		"The book with the same title already exists.":            "Buku dengan judul yang sama sudah ada.",
+		"Books older than 10 years cannot be deleted.":            "Buku yang lebih tua dari 10 tahun tidak dapat dihapus.",
+		"Internal server error.":                                  "Terjadi kesalahan pada server.",
+		"Unauthorized.":                                           "Tidak terautentikasi.",
+		"Request body is too large.":                              "Isi permintaan terlalu besar.",
+		"Content-Type must be JSON or JSON:API.":                  "Content-Type harus JSON atau JSON:API.",
+		"Content-Encoding must be gzip.":                          "Content-Encoding harus gzip.",
+		"Request body is not valid gzip.":                         "Isi permintaan bukan gzip yang valid.",
+		"None of the accepted formats is supported.":              "Tidak ada format yang diminta yang didukung.",
+		"The fields parameter contains an unknown field.":         "Parameter fields berisi kolom yang tidak dikenal.",
+		"page[number] must be a positive number.":                 "page[number] harus berupa angka positif.",
+		"page[size] must be between 1 and 100.":                   "page[size] harus antara 1 dan 100.",
+		"The origin is not allowed.":                              "Origin tidak diizinkan.",
+		"Too many requests, please try again later.":              "Terlalu banyak permintaan, silakan coba lagi nanti.",
+		"The request body must be a JSON:API resource object.":    "Isi permintaan harus berupa resource object JSON:API.",
+		"The type of the resource does not match the endpoint.":   "Tipe resource tidak sesuai dengan endpoint.",
+		"The id of the resource does not match the URL.":          "id resource tidak sesuai dengan URL.",
+		"The request took too long and was cancelled.":            "Permintaan memakan waktu terlalu lama dan dibatalkan.",
+	},
+}
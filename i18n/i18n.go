@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/rs/zerolog/log"
+)
+
+// FallbackLocale is used when the client accepts none of the supported locales.
+const FallbackLocale = "en"
+
+const translatorKey = "translator"
+
+var universal = ut.New(en.New(), en.New(), id.New())
+
+func init() {
+	for locale, messages := range catalogues {
+		translator, _ := universal.GetTranslator(locale)
+		for key, text := range messages {
+			if err := translator.Add(key, text, false); err != nil {
+				log.Error().Err(err).Str("locale", locale).Str("key", key).Msg("[I18n] Failed to add translation")
+			}
+		}
+	}
+}
+
+// Translator returns the translator for the locale negotiated from the
+// Accept-Language header, and remembers it for the rest of the request.
+func Translator(c *gin.Context) ut.Translator {
+	if translator, ok := c.Get(translatorKey); ok {
+		return translator.(ut.Translator)
+	}
+
+	translator, _ := universal.FindTranslator(acceptedLocales(c.GetHeader("Accept-Language"))...)
+	c.Set(translatorKey, translator)
+	c.Header("Content-Language", translator.Locale())
+	return translator
+}
+
+// T translates the message for the request locale. Messages without a
+// translation, such as error details, are returned unchanged.
+func T(c *gin.Context, key string, params ...string) string {
+	if message, err := Translator(c).T(key, params...); err == nil {
+		return message
+	}
+	if message, err := universal.GetFallback().T(key, params...); err == nil {
+		return message
+	}
+	return key
+}
+
+// acceptedLocales parses an Accept-Language header into locale names ordered
+// by preference, adding the base language after each regional variant.
+func acceptedLocales(header string) []string {
+	type accepted struct {
+		locale  string
+		quality float64
+	}
+
+	var languages []accepted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		languages = append(languages, accepted{locale: strings.ToLower(tag), quality: quality})
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].quality > languages[j].quality })
+
+	var locales []string
+	for _, language := range languages {
+		if language.quality <= 0 {
+			continue
+		}
+		base, region, found := strings.Cut(language.locale, "-")
+		if found {
+			locales = append(locales, base+"_"+strings.ToUpper(region))
+		}
+		locales = append(locales, base)
+	}
+	return append(locales, FallbackLocale)
+}
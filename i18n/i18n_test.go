@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleNegotiation(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantLocales []string
+		wantLocale  string
+	}{
+		{name: "No Header", header: "", wantLocales: []string{"en"}, wantLocale: "en"},
+		{name: "Supported Language", header: "id", wantLocales: []string{"id", "en"}, wantLocale: "id"},
+		{name: "Region Falls Back To Its Language", header: "id-ID", wantLocales: []string{"id_ID", "id", "en"}, wantLocale: "id"},
+		{name: "Tags Ignore Case", header: "ID-id", wantLocales: []string{"id_ID", "id", "en"}, wantLocale: "id"},
+		{name: "Ordered By Q-Value", header: "en;q=0.3, id;q=0.8", wantLocales: []string{"id", "en", "en"}, wantLocale: "id"},
+		{name: "Equal Q-Values Keep Their Order", header: "en, id", wantLocales: []string{"en", "id", "en"}, wantLocale: "en"},
+		{name: "Unsupported Language Is Skipped", header: "fr-FR, id;q=0.5", wantLocales: []string{"fr_FR", "fr", "id", "en"}, wantLocale: "id"},
+		{name: "Zero Q-Value Is Refused", header: "id;q=0, fr", wantLocales: []string{"fr", "en"}, wantLocale: "en"},
+		{name: "Wildcard Falls Back To English", header: "*", wantLocales: []string{"en"}, wantLocale: "en"},
+		{name: "Only Unsupported Languages", header: "fr, de;q=0.9", wantLocales: []string{"fr", "de", "en"}, wantLocale: "en"},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLocales, acceptedLocales(tt.header))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("Accept-Language", tt.header)
+
+			assert.Equal(t, tt.wantLocale, Translator(c).Locale())
+			assert.Equal(t, tt.wantLocale, w.Header().Get("Content-Language"))
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		key    string
+		want   string
+	}{
+		{name: "English", header: "en", key: "validation.required", want: "This field is required"},
+		{name: "Indonesian", header: "id-ID", key: "validation.required", want: "Kolom ini wajib diisi"},
+		{name: "Unknown Language", header: "fr", key: "validation.required", want: "This field is required"},
+		{name: "Untranslated Message", header: "id", key: "Some error detail.", want: "Some error detail."},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("Accept-Language", tt.header)
+
+			assert.Equal(t, tt.want, T(c, tt.key))
+		})
+	}
+}
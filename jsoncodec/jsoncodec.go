@@ -0,0 +1,85 @@
+// Package jsoncodec lets the JSON library used for responses and request
+// bodies be chosen at runtime. Gin itself can only switch libraries with
+// build tags.
+package jsoncodec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bytedance/sonic"
+	gojson "github.com/goccy/go-json"
+)
+
+// Encoder writes JSON values to a stream.
+type Encoder interface {
+	Encode(v any) error
+	SetEscapeHTML(on bool)
+}
+
+// Decoder reads JSON values from a stream.
+type Decoder interface {
+	Decode(v any) error
+	DisallowUnknownFields()
+	UseNumber()
+}
+
+// Codec is a JSON library.
+type Codec struct {
+	Name       string
+	Marshal    func(v any) ([]byte, error)
+	Unmarshal  func(data []byte, v any) error
+	NewEncoder func(w io.Writer) Encoder
+	NewDecoder func(r io.Reader) Decoder
+}
+
+// Std is encoding/json from the standard library.
+var Std = Codec{
+	Name:       "std",
+	Marshal:    json.Marshal,
+	Unmarshal:  json.Unmarshal,
+	NewEncoder: func(w io.Writer) Encoder { return json.NewEncoder(w) },
+	NewDecoder: func(r io.Reader) Decoder { return json.NewDecoder(r) },
+}
+
+// Sonic is bytedance/sonic configured to behave like encoding/json. It only
+// uses its JIT on amd64 and arm64 with supported Go versions, and falls back
+// to encoding/json elsewhere.
+var Sonic = Codec{
+	Name:       "sonic",
+	Marshal:    sonic.ConfigStd.Marshal,
+	Unmarshal:  sonic.ConfigStd.Unmarshal,
+	NewEncoder: func(w io.Writer) Encoder { return sonic.ConfigStd.NewEncoder(w) },
+	NewDecoder: func(r io.Reader) Decoder { return sonic.ConfigStd.NewDecoder(r) },
+}
+
+// GoJSON is goccy/go-json, a drop-in replacement for encoding/json.
+var GoJSON = Codec{
+	Name:       "go-json",
+	Marshal:    gojson.Marshal,
+	Unmarshal:  gojson.Unmarshal,
+	NewEncoder: func(w io.Writer) Encoder { return gojson.NewEncoder(w) },
+	NewDecoder: func(r io.Reader) Decoder { return gojson.NewDecoder(r) },
+}
+
+var codecs = map[string]Codec{
+	Std.Name:    Std,
+	Sonic.Name:  Sonic,
+	GoJSON.Name: GoJSON,
+}
+
+// ByName returns the codec with the given name: std, sonic or go-json.
+func ByName(name string) (Codec, error) {
+	codec, ok := codecs[name]
+	if !ok {
+		names := make([]string, 0, len(codecs))
+		for name := range codecs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return Codec{}, fmt.Errorf("unknown JSON codec %q, expected one of %v", name, names)
+	}
+	return codec, nil
+}
@@ -2,48 +2,200 @@ package main
 
 import (
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/docs"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/handler"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/jsoncodec"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/metrics"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/middleware"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/ratelimit"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/repository"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/router"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/service"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/tracing"
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	// run returns only after its deferred cleanup, so the database and tracer are closed before exiting
+	if err := run(); err != nil {
+		log.Fatal().Err(err).Msg("Server failed")
+	}
+}
+
+func run() error {
 	// Initialize logging
-	config.InitializeLogger()
+	logLevels := config.InitializeLogger()
 
 	//  Preparing the context
 	ctx := context.Background()
 
+	// Initialize tracing
+	shutdownTracer, err := tracing.InitializeTracer(ctx, config.GetTracingConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	defer shutdownTracer(ctx)
+
 	// Loading database connection from config
 	db, err := config.LoadDatabase(ctx)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to connect to the database: %w", err)
 	}
 	defer db.Close()
 
+	metrics.RegisterDBStats(db, config.GetDBConfig().Name)
+
 	// Initialize repositories, services, and handlers
-	bookRepository := repository.NewMySQLBookRepository(db)
-	bookService := service.NewBookService(bookRepository)
+	bookRepository := repository.NewInstrumentedBookRepository(repository.NewMySQLBookRepository(db))
+	bookService := service.NewTracedBookService(service.NewBookService(bookRepository))
 	bookHandler := handler.NewBookHandler(bookService)
+	adminConfig := config.GetAdminConfig()
+	adminHandler := handler.NewAdminHandler(logLevels, adminConfig)
+	healthHandler := handler.NewHealthHandler(db, 2*time.Second)
 
 	// Initialize the router
-	router := gin.Default()
+	serverConfig := config.GetServerConfig()
+	engine := gin.New()
+	// Only trusted proxies may set the client IP through X-Forwarded-For, as clients are rate limited by it
+	if err := engine.SetTrustedProxies(serverConfig.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	engine.Use(
+		middleware.RequestID(),
+		middleware.ClientCert(),
+		middleware.Tracing(),
+		middleware.Metrics(),
+		middleware.AccessLog(config.GetAccessLogConfig().ExcludePaths),
+		middleware.Recovery(),
+	)
+
+	// Let browsers on other origins call the API
+	corsConfig := config.GetCORSConfig()
+	if len(corsConfig.AllowedOrigins) > 0 {
+		engine.Use(middleware.CORS(corsConfig))
+	}
+
+	// Limit the requests per client, after CORS so preflights are not counted
+	rateLimitConfig, err := config.GetRateLimitConfig()
+	if err != nil {
+		return err
+	}
+	if rateLimitConfig.Enabled {
+		engine.Use(middleware.RateLimit(ratelimit.NewMemoryStore(), rateLimitConfig, adminConfig.Token))
+	}
+
+	// Cancel the database queries of requests that run past their deadline
+	engine.Use(middleware.RequestTimeout(config.GetRequestTimeoutConfig()))
+
+	// Compress responses and accept gzip request bodies
+	compressionConfig := config.GetCompressionConfig()
+	if compressionConfig.Enabled {
+		engine.Use(middleware.Compression(compressionConfig.MinSize, compressionConfig.ContentTypes))
+	}
+	if compressionConfig.GzipRequests {
+		engine.Use(middleware.GzipRequests())
+	}
+
+	// Limit request bodies and reject unknown or duplicate JSON fields
+	apiConfig := config.GetAPIConfig()
+	binding.EnableDecoderDisallowUnknownFields = apiConfig.StrictJSON
+	engine.Use(middleware.RequestBody(apiConfig.MaxBodySize))
+
+	// Links in responses point at the public address when the API runs behind a proxy
+	helper.PublicBaseURL = apiConfig.PublicBaseURL
+
+	// Select the JSON library for responses and request bodies
+	helper.JSONCodec, err = jsoncodec.ByName(apiConfig.JSONCodec)
+	if err != nil {
+		return fmt.Errorf("failed to select the JSON codec: %w", err)
+	}
+
+	// Validate requests against the OpenAPI document
+	if apiConfig.ValidateRequests {
+		openAPIRouter, err := docs.NewRouter()
+		if err != nil {
+			return fmt.Errorf("failed to load the OpenAPI document: %w", err)
+		}
+		engine.Use(middleware.OpenAPIValidator(openAPIRouter, apiConfig.ValidateResponses))
+	}
 
 	// Register routes
-	router.GET("/books", bookHandler.GetAllBooks)
-	router.GET("/books/:id", bookHandler.GetBookByID)
-	router.POST("/books", bookHandler.CreateBook)
-	router.PUT("/books/:id", bookHandler.UpdateBook)
-	router.DELETE("/books/:id", bookHandler.DeleteBook)
+	router.RegisterRoutes(engine, router.Handlers{
+		Book:   bookHandler,
+		Admin:  adminHandler,
+		Health: healthHandler,
+	}, apiConfig)
+
+	helper.StreamWriteTimeout = serverConfig.WriteTimeout
+	server := &http.Server{
+		Addr:              serverConfig.Address(),
+		Handler:           engine,
+		ReadTimeout:       serverConfig.ReadTimeout,
+		ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+		WriteTimeout:      serverConfig.WriteTimeout,
+		IdleTimeout:       serverConfig.IdleTimeout,
+	}
 
-	url := fmt.Sprintf("%s:%s", os.Getenv("HOST"), os.Getenv("PORT"))
-	log.Info().Msgf("Server running at http://%s/", url)
+	// Stop on SIGINT or SIGTERM
+	signalCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	tlsConfig := config.GetTLSConfig()
+	if tlsConfig.Enabled() {
+		server.TLSConfig, err = config.LoadTLSConfig(signalCtx, tlsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load the TLS configuration: %w", err)
+		}
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			log.Info().Msgf("Server running at https://%s/", server.Addr)
+			// The certificate is served from TLSConfig so it can be reloaded
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			log.Info().Msgf("Server running at http://%s/", server.Addr)
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("server stopped unexpectedly: %w", err)
+	case <-signalCtx.Done():
+		stop()
+	}
+
+	// Fail readiness first so the load balancer stops sending new requests
+	log.Info().Dur("drain_delay", serverConfig.DrainDelay).Msg("Shutdown signal received, draining")
+	healthHandler.SetDraining(true)
+	time.Sleep(serverConfig.DrainDelay)
+
+	// Wait for in-flight requests up to the deadline, the database is closed afterwards by the deferred db.Close
+	shutdownCtx, cancel := context.WithTimeout(ctx, serverConfig.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to drain in-flight requests before the deadline: %w", err)
+	}
 
-	router.Run(url)
+	log.Info().Msg("Server stopped gracefully")
+	return nil
 }
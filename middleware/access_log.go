@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// UserKey is the gin context key holding the authenticated user, if any.
+const UserKey = "user"
+
+// AccessLog writes one structured log line per request, skipping the excluded paths.
+func AccessLog(excludePaths []string) gin.HandlerFunc {
+	excluded := make(map[string]bool, len(excludePaths))
+	for _, path := range excludePaths {
+		excluded[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if excluded[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		logger := zerolog.Ctx(c.Request.Context())
+
+		event := logger.Info()
+		switch {
+		case status >= 500:
+			event = logger.Error()
+		case status >= 400:
+			event = logger.Warn()
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		event.
+			Str("method", c.Request.Method).
+			Str("route", route).
+			Str("path", c.Request.URL.Path).
+			Int("status", status).
+			Dur("latency", time.Since(start)).
+			Int("bytes", c.Writer.Size()).
+			Str("client_ip", c.ClientIP()).
+			Str("user", c.GetString(UserKey)).
+			Str("user_agent", c.Request.UserAgent()).
+			Msg("[AccessLog] Request handled")
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIdentityKey is the gin context key holding the verified client certificate identity.
+const ClientIdentityKey = "client_identity"
+
+// ClientCert exposes the identity of a verified mTLS client certificate to
+// handlers, using the first DNS name or the subject common name.
+func ClientCert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS != nil && len(c.Request.TLS.VerifiedChains) > 0 {
+			certificate := c.Request.TLS.VerifiedChains[0][0]
+
+			identity := certificate.Subject.CommonName
+			if len(certificate.DNSNames) > 0 {
+				identity = certificate.DNSNames[0]
+			}
+
+			c.Set(ClientIdentityKey, identity)
+			c.Set(UserKey, identity)
+		}
+
+		c.Next()
+	}
+}
+
+// GetClientIdentity returns the identity set by the ClientCert middleware, if any.
+func GetClientIdentity(c *gin.Context) string {
+	return c.GetString(ClientIdentityKey)
+}
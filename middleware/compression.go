@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// brotliLevel trades some compression for speed, as responses are compressed on every request.
+const brotliLevel = 5
+
+var encoderPools = map[string]*sync.Pool{
+	"br": {New: func() any { return brotli.NewWriterLevel(nil, brotliLevel) }},
+	"gzip": {New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	}},
+}
+
+// resettableWriter is implemented by the gzip and brotli writers, so they can be pooled.
+type resettableWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// Compression compresses responses with brotli or gzip, as negotiated from the
+// Accept-Encoding header. Only responses of at least minSize bytes whose
+// Content-Type matches contentTypes are compressed, where "text/*" matches
+// every text type. Responses that are flushed early, such as streams, are
+// compressed whatever their size.
+func Compression(minSize int, contentTypes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        minSize,
+			contentTypes:   contentTypes,
+		}
+		c.Writer = writer
+		defer func() {
+			if err := writer.close(); err != nil {
+				zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("[Compression] Failed to write compressed response")
+			}
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks brotli or gzip from an Accept-Encoding header,
+// preferring brotli when both are equally acceptable, or "" for neither. A
+// "*" stands for the codings the header does not name.
+func negotiateEncoding(header string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "br" && coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		qualities[coding] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, coding := range []string{"br", "gzip"} {
+		quality, ok := qualities[coding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = coding, quality
+		}
+	}
+	return best
+}
+
+// compressWriter holds the response back until it is large enough to be
+// worth compressing, then writes it through a pooled encoder.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding     string
+	minSize      int
+	contentTypes []string
+
+	buffer  []byte
+	decided bool
+	encoder resettableWriter
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.write(data)
+	}
+
+	w.buffer = append(w.buffer, data...)
+	if len(w.buffer) >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow commits the headers, so compression is decided on the content type alone.
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(true)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(true)
+	}
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Written also counts the buffered response, so nothing else is written after it.
+func (w *compressWriter) Written() bool {
+	return len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend the write deadline of a stream.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) write(data []byte) (int, error) {
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// decide sets the headers for a compressed response, when compress is set and
+// the response qualifies, or for an uncompressed one and writes out the buffer.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+
+	header := w.Header()
+	compressible := w.compressible()
+	if compressible {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if compressible && compress && header.Get("Content-Encoding") == "" && bodyAllowed(w.Status()) {
+		w.encoder = encoderPools[w.encoding].Get().(resettableWriter)
+		w.encoder.Reset(w.ResponseWriter)
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+	}
+
+	buffer := w.buffer
+	w.buffer = nil
+	if len(buffer) == 0 {
+		return nil
+	}
+	_, err := w.write(buffer)
+	return err
+}
+
+// close writes out a response that stayed below the minimum size and finishes the compressed stream.
+func (w *compressWriter) close() error {
+	if !w.decided {
+		if len(w.buffer) == 0 {
+			return nil
+		}
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.encoder == nil {
+		return nil
+	}
+
+	err := w.encoder.Close()
+	w.encoder.Reset(nil)
+	encoderPools[w.encoding].Put(w.encoder)
+	w.encoder = nil
+	return err
+}
+
+func (w *compressWriter) compressible() bool {
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, allowed := range w.contentTypes {
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// GzipRequests decompresses request bodies sent with Content-Encoding: gzip
+// and rejects other encodings with 415. It must run before RequestBody, so the
+// size limit applies to the decompressed body.
+func GzipRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := strings.ToLower(strings.TrimSpace(c.GetHeader("Content-Encoding")))
+		switch encoding {
+		case "", "identity":
+			c.Next()
+			return
+		case "gzip":
+			if c.Request.ContentLength == 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+				c.Next()
+				return
+			}
+		default:
+			zerolog.Ctx(c.Request.Context()).Error().Str("content_encoding", encoding).Msg("[GzipRequests] Unsupported content encoding")
+			c.Header("Accept-Encoding", "gzip")
+			helper.SendErrorResponse(c, http.StatusUnsupportedMediaType, "Content-Encoding must be gzip.", nil)
+			c.Abort()
+			return
+		}
+
+		reader, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			zerolog.Ctx(c.Request.Context()).Error().Err(err).Msg("[GzipRequests] Invalid gzip request body")
+			helper.SendErrorResponse(c, http.StatusBadRequest, "Request body is not valid gzip.", nil)
+			c.Abort()
+			return
+		}
+		defer reader.Close()
+
+		c.Request.Body = reader
+		c.Request.ContentLength = -1
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+		c.Next()
+	}
+}
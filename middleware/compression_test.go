@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: "identity", want: ""},
+		{header: "gzip", want: "gzip"},
+		{header: "br", want: "br"},
+		{header: "gzip, br", want: "br"},
+		{header: "GZIP, deflate", want: "gzip"},
+		{header: "br;q=0.5, gzip;q=0.8", want: "gzip"},
+		{header: "br;q=0, gzip", want: "gzip"},
+		{header: "br;q=0, gzip;q=0", want: ""},
+		{header: "gzip;q=abc", want: "gzip"},
+		{header: "*", want: "br"},
+		{header: "*;q=0", want: ""},
+		{header: "br;q=0, *", want: "gzip"},
+		{header: "gzip;q=0.5, *;q=0.2", want: "gzip"},
+		{header: "br;q=0.1, *;q=0.2", want: "gzip"},
+		{header: "gzip, *;q=0", want: "gzip"},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.header
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateEncoding(tt.header))
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	large := strings.Repeat(`{"title":"Book"}`, 100)
+	small := `{"title":"Book"}`
+
+	engine := gin.New()
+	engine.Use(Compression(1024, []string{"application/json", "text/*"}))
+	respond := func(contentType, body string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Header("Content-Length", strconv.Itoa(len(body)))
+			c.Data(http.StatusOK, contentType, []byte(body))
+		}
+	}
+	engine.GET("/large", respond("application/json; charset=utf-8", large))
+	engine.GET("/small", respond("application/json; charset=utf-8", small))
+	engine.GET("/text", respond("text/csv; charset=utf-8", large))
+	engine.GET("/binary", respond("application/msgpack", large))
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantEncoding   string
+		wantBody       string
+	}{
+		{name: "Large JSON With Brotli", path: "/large", acceptEncoding: "gzip, br", wantEncoding: "br", wantBody: large},
+		{name: "Large JSON With Gzip", path: "/large", acceptEncoding: "br;q=0, *", wantEncoding: "gzip", wantBody: large},
+		{name: "Text Type Matches text/*", path: "/text", acceptEncoding: "gzip", wantEncoding: "gzip", wantBody: large},
+		{name: "Below The Minimum Size", path: "/small", acceptEncoding: "gzip", wantBody: small},
+		{name: "Content Type Not Listed", path: "/binary", acceptEncoding: "gzip", wantBody: large},
+		{name: "No Acceptable Encoding", path: "/large", acceptEncoding: "identity", wantBody: large},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+
+			var body io.Reader = w.Body
+			switch tt.wantEncoding {
+			case "":
+				// Uncompressed responses keep the length set by the handler
+				assert.Equal(t, strconv.Itoa(len(tt.wantBody)), w.Header().Get("Content-Length"))
+			case "gzip":
+				reader, err := gzip.NewReader(w.Body)
+				assert.NoError(t, err)
+				body = reader
+			case "br":
+				body = brotli.NewReader(w.Body)
+			}
+			if tt.wantEncoding != "" {
+				assert.Empty(t, w.Header().Get("Content-Length"), "the length of the uncompressed body is dropped")
+				assert.Less(t, w.Body.Len(), len(tt.wantBody))
+			}
+
+			decoded, err := io.ReadAll(body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBody, string(decoded))
+		})
+	}
+}
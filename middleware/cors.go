@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// CORS lets browsers on the configured origins call the API. An origin may
+// contain one "*" matching any part of it, e.g. "https://*.example.com", and
+// "*" alone allows every origin. Preflight requests are answered here with 204
+// before they reach routing, so every route accepts them; preflights from an
+// origin that is not allowed are rejected with 403. It must run before the
+// OpenAPI validator, which does not know the OPTIONS method.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	allowAll := false
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+	}
+	allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		header := c.Writer.Header()
+		// The response depends on the origin unless every origin gets the same "*"
+		if !allowAll || cfg.AllowCredentials {
+			header.Add("Vary", "Origin")
+		}
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" {
+			c.Next()
+			return
+		}
+		if !allowAll && !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				zerolog.Ctx(c.Request.Context()).Warn().Str("origin", origin).Msg("[CORS] Preflight from an origin that is not allowed")
+				helper.SendErrorResponse(c, http.StatusForbidden, "The origin is not allowed.", nil)
+				c.Abort()
+				return
+			}
+			// Browsers block the response themselves without the CORS headers
+			c.Next()
+			return
+		}
+
+		if allowAll && !cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			// Credentials are not allowed with "*", so the origin is echoed back
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposedHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		header.Set("Access-Control-Allow-Methods", allowedMethods)
+		if allowedHeaders == "*" {
+			// Echoed back, as "*" does not cover Authorization and is ignored with credentials
+			header.Set("Access-Control-Allow-Headers", c.GetHeader("Access-Control-Request-Headers"))
+		} else if allowedHeaders != "" {
+			header.Set("Access-Control-Allow-Headers", allowedHeaders)
+		}
+		if cfg.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// originAllowed reports whether origin matches one of the allowed origins, where a "*" matches any part of it.
+func originAllowed(allowed []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			if pattern == origin {
+				return true
+			}
+			continue
+		}
+		if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
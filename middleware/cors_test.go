@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.example.org", "http://localhost:*"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://app.example.com", want: true},
+		{origin: "https://APP.example.com", want: true},
+		{origin: "https://other.example.com", want: false},
+		{origin: "https://api.example.org", want: true},
+		{origin: "https://a.b.example.org", want: true},
+		{origin: "https://.example.org", want: false},
+		{origin: "https://example.org", want: false},
+		{origin: "https://evil-example.org", want: false},
+		{origin: "http://api.example.org", want: false},
+		{origin: "https://api.example.org.evil.com", want: false},
+		{origin: "http://localhost:3000", want: true},
+		{origin: "http://localhost:", want: false},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.origin
+		t.Run(tt.origin, func(t *testing.T) {
+			assert.Equal(t, tt.want, originAllowed(allowed, tt.origin))
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	engine := gin.New()
+	engine.Use(CORS(config.CORSConfig{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		ExposedHeaders: []string{"RateLimit-Remaining"},
+		MaxAge:         10 * time.Minute,
+	}))
+	engine.GET("/books", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		preflight  bool
+		wantStatus int
+		wantHeader map[string]string
+		wantVary   []string
+	}{
+		{
+			name:       "Preflight From A Wildcard Subdomain",
+			method:     http.MethodOptions,
+			origin:     "https://app.example.com",
+			preflight:  true,
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization",
+				"Access-Control-Max-Age":       "600",
+			},
+			wantVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "Preflight From A Disallowed Origin",
+			method:     http.MethodOptions,
+			origin:     "https://example.net",
+			preflight:  true,
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "Request From An Allowed Origin",
+			method:     http.MethodGet,
+			origin:     "https://app.example.com",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":   "https://app.example.com",
+				"Access-Control-Expose-Headers": "RateLimit-Remaining",
+				"Access-Control-Allow-Methods":  "",
+			},
+			wantVary: []string{"Origin"},
+		},
+		{
+			name:       "Request From A Disallowed Origin",
+			method:     http.MethodGet,
+			origin:     "https://example.net",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:       "Request Without An Origin",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin"},
+		},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/books", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "content-type")
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			for name, want := range tt.wantHeader {
+				assert.Equal(t, want, w.Header().Get(name), name)
+			}
+			assert.Equal(t, tt.wantVary, w.Header().Values("Vary"))
+		})
+	}
+
+	// Define test for case Any Origin Without Credentials
+	t.Run("Any Origin Without Credentials", func(t *testing.T) {
+		engine := gin.New()
+		engine.Use(CORS(config.CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}))
+		engine.GET("/books", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("Origin", "https://anywhere.test")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Values("Vary"))
+	})
+}
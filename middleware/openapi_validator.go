@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/i18n"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/rs/zerolog"
+)
+
+// errorMessageExtension lets a parameter in the OpenAPI document define the message returned when it is invalid.
+const errorMessageExtension = "x-error-message"
+
+func init() {
+	// JSON:API documents are JSON with their own media type
+	openapi3filter.RegisterBodyDecoder(helper.MIMEJSONAPI, openapi3filter.JSONBodyDecoder)
+	// NDJSON streams are documented as plain strings
+	openapi3filter.RegisterBodyDecoder(helper.MIMENDJSON, openapi3filter.RegisteredBodyDecoder("text/plain"))
+	// YAML responses share the JSON schemas, where timestamps are date-time strings
+	openapi3filter.RegisterBodyDecoder(binding.MIMEYAML2, yamlBodyDecoder(openapi3filter.RegisteredBodyDecoder(binding.MIMEYAML2)))
+}
+
+// yamlBodyDecoder wraps the YAML body decoder so timestamps, which YAML decodes
+// as time.Time, are validated as the RFC 3339 strings JSON has in their place.
+func yamlBodyDecoder(decode openapi3filter.BodyDecoder) openapi3filter.BodyDecoder {
+	return func(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encFn openapi3filter.EncodingFn) (any, error) {
+		value, err := decode(body, header, schema, encFn)
+		if err != nil {
+			return nil, err
+		}
+		return formatTimes(value), nil
+	}
+}
+
+func formatTimes(value any) any {
+	switch value := value.(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case map[string]any:
+		for key, item := range value {
+			value[key] = formatTimes(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = formatTimes(item)
+		}
+	}
+	return value
+}
+
+// OpenAPIValidator validates path parameters, query parameters and JSON bodies
+// against the OpenAPI document before the request reaches a handler. Requests
+// for routes missing from the document are passed through unchanged. When
+// validateResponses is set, responses are buffered and replaced with a 500 if
+// they do not match the document, which is meant for tests.
+func OpenAPIValidator(router routers.Router, validateResponses bool) gin.HandlerFunc {
+	options := &openapi3filter.Options{
+		MultiError: true,
+		// Authentication is enforced by the route middlewares
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		logger := zerolog.Ctx(c.Request.Context())
+
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), requestInput); err != nil {
+			logger.Error().Err(err).Msg("[OpenAPIValidator] Request does not match the OpenAPI document")
+			sendRequestValidationError(c, err)
+			c.Abort()
+			return
+		}
+
+		if !validateResponses {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 writer.Status(),
+			Header:                 writer.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(writer.body.Bytes())),
+			Options:                options,
+		}
+		if err := openapi3filter.ValidateResponse(c.Request.Context(), responseInput); err != nil {
+			logger.Error().Err(err).Msg("[OpenAPIValidator] Response does not match the OpenAPI document")
+			helper.SendErrorResponse(c, http.StatusInternalServerError, "Response does not match the OpenAPI document.", err.Error())
+			return
+		}
+
+		c.Writer.Write(writer.body.Bytes())
+	}
+}
+
+// sendRequestValidationError responds with 400 for invalid parameters or
+// malformed bodies, with 413 for bodies over the size limit, with 415 for a
+// body in a media type the operation does not accept and with 422 when the
+// body does not match its schema.
+func sendRequestValidationError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		helper.SendErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body is too large.", nil)
+		return
+	}
+
+	var details []models.ValidationErrorDetail
+	jsonAPI := helper.IsJSONAPIRequest(c)
+
+	for _, requestErr := range requestErrors(err) {
+		if requestErr.Parameter != nil {
+			message, ok := requestErr.Parameter.Extensions[errorMessageExtension].(string)
+			if !ok {
+				message = fmt.Sprintf("Invalid %s parameter %q.", requestErr.Parameter.In, requestErr.Parameter.Name)
+			}
+			helper.SendErrorResponse(c, http.StatusBadRequest, message, nil)
+			return
+		}
+
+		// kin-openapi reports an undocumented media type only in the reason, without an error to inspect
+		if body := requestErr.RequestBody; body != nil && requestErr.Err == nil && body.Content.Get(c.GetHeader("Content-Type")) == nil {
+			helper.SendErrorResponse(c, http.StatusUnsupportedMediaType, "Content-Type must be JSON or JSON:API.", nil)
+			return
+		}
+
+		schemaErrors := schemaErrors(requestErr.Err)
+		if len(schemaErrors) == 0 {
+			helper.SendErrorResponse(c, http.StatusBadRequest, requestErr.Error(), nil)
+			return
+		}
+		for _, schemaErr := range schemaErrors {
+			pointer := schemaErr.JSONPointer()
+			// Fields of a JSON:API document are named as in a JSON body, the error object points into data.attributes
+			if jsonAPI {
+				if len(pointer) <= 2 || pointer[0] != "data" || pointer[1] != "attributes" {
+					helper.SendErrorResponse(c, http.StatusBadRequest, "The request body must be a JSON:API resource object.", nil)
+					return
+				}
+				pointer = pointer[2:]
+			}
+			details = append(details, models.ValidationErrorDetail{
+				Field:   strings.Join(pointer, "."),
+				Message: schemaErrorMessage(c, schemaErr),
+			})
+		}
+	}
+
+	if len(details) == 0 {
+		helper.SendErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	helper.SendValidationErrorResponse(c, details)
+}
+
+func requestErrors(err error) []*openapi3filter.RequestError {
+	var multi openapi3.MultiError
+	if !errors.As(err, &multi) {
+		multi = openapi3.MultiError{err}
+	}
+
+	var result []*openapi3filter.RequestError
+	for _, e := range multi {
+		var requestErr *openapi3filter.RequestError
+		if errors.As(e, &requestErr) {
+			result = append(result, requestErr)
+		}
+	}
+	return result
+}
+
+// schemaErrors flattens the possibly nested multi errors returned for a body into schema errors.
+func schemaErrors(err error) []*openapi3.SchemaError {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var result []*openapi3.SchemaError
+		for _, e := range multi {
+			result = append(result, schemaErrors(e)...)
+		}
+		return result
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []*openapi3.SchemaError{schemaErr}
+	}
+	return nil
+}
+
+func schemaErrorMessage(c *gin.Context, err *openapi3.SchemaError) string {
+	switch err.SchemaField {
+	case "required":
+		return i18n.T(c, "validation.required")
+	case "minLength":
+		return i18n.T(c, "validation.empty")
+	case "maxLength":
+		return i18n.T(c, "validation.max.string", strconv.FormatUint(*err.Schema.MaxLength, 10))
+	}
+	return err.Reason
+}
+
+// bufferedResponseWriter holds the response body back until it has been validated.
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/docs"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIValidator(t *testing.T) {
+	openAPIRouter, err := docs.NewRouter()
+	if err != nil {
+		t.Fatalf("Error loading OpenAPI document: %v", err)
+	}
+
+	engine := gin.New()
+	// Cut bodies off like RequestBody does, with a limit small enough for the test
+	engine.Use(func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 256)
+	})
+	engine.Use(OpenAPIValidator(openAPIRouter, false))
+	engine.Any("/*path", func(c *gin.Context) { c.String(http.StatusOK, "reached") })
+
+	const validBook = `{"title":"Go","author":"Rob","year":2015}`
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		// wantMessage is the message of an error envelope, wantFields the fields of a 422
+		wantMessage string
+		wantFields  []string
+	}{
+		{
+			name:        "Query Parameter With Its Own Message",
+			method:      http.MethodGet,
+			target:      "/api/v1/books?fields=isbn",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "The fields parameter contains an unknown field.",
+		},
+		{
+			name:        "Query Parameter Out Of Range",
+			method:      http.MethodGet,
+			target:      "/api/v1/books?page[size]=1000",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "page[size] must be between 1 and 100.",
+		},
+		{
+			name:        "Path Parameter",
+			method:      http.MethodGet,
+			target:      "/api/v1/books/abc",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "ID must be a valid number.",
+		},
+		{
+			name:        "Body Missing A Required Field",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: "application/json",
+			body:        `{"title":"Go","author":"Rob"}`,
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantMessage: "validation error",
+			wantFields:  []string{"year"},
+		},
+		{
+			name:        "Body With Fields Of The Wrong Type",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: "application/json",
+			body:        `{"title":1,"author":"Rob","year":"2015"}`,
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantMessage: "validation error",
+			wantFields:  []string{"title", "year"},
+		},
+		{
+			name:        "JSON:API Body Naming The Attribute",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: helper.MIMEJSONAPI,
+			body:        `{"data":{"type":"books","attributes":{"title":"Go","author":"Rob"}}}`,
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantMessage: "validation error",
+			wantFields:  []string{"year"},
+		},
+		{
+			name:        "JSON:API Body Without A Resource Object",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: helper.MIMEJSONAPI,
+			body:        validBook,
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "The request body must be a JSON:API resource object.",
+		},
+		{
+			name:        "Malformed Body",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: "application/json",
+			body:        `{"title":`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "Wrong Content Type",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: "text/plain",
+			body:        validBook,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: "Content-Type must be JSON or JSON:API.",
+		},
+		{
+			name:        "Body Over The Size Limit",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: "application/json",
+			body:        `{"title":"` + strings.Repeat("a", 300) + `","author":"Rob","year":2015}`,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: "Request body is too large.",
+		},
+		{
+			name:        "Valid Body Reaches The Handler",
+			method:      http.MethodPost,
+			target:      "/api/v1/books",
+			contentType: "application/json",
+			body:        validBook,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			// The admin routes check the token themselves, so the security requirement is not enforced here
+			name:       "Authentication Is Left To The Route",
+			method:     http.MethodGet,
+			target:     "/admin/log-level",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Undocumented Route Passes Through",
+			method:     http.MethodGet,
+			target:     "/not-documented?fields=isbn",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "reached", w.Body.String())
+				return
+			}
+
+			var envelope struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+				Errors  []struct {
+					Field string `json:"field"`
+				} `json:"errors"`
+			}
+			if !assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope), w.Body.String()) {
+				return
+			}
+			assert.Equal(t, tt.wantStatus, envelope.Code)
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, envelope.Message)
+			}
+			var fields []string
+			for _, e := range envelope.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, fields)
+		})
+	}
+}
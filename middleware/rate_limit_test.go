@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/ratelimit"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubStore returns a fixed result and records the keys it was asked for.
+type stubStore struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+	limits []ratelimit.Limit
+}
+
+func (s *stubStore) Take(_ context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	s.limits = append(s.limits, limit)
+	return s.result, s.err
+}
+
+func newRateLimitEngine(store ratelimit.Store) *gin.Engine {
+	engine := gin.New()
+	engine.Use(RateLimit(store, config.RateLimitConfig{
+		ReadRequests:  300,
+		WriteRequests: 60,
+		Window:        time.Minute,
+		APIKeys:       []string{"known-key"},
+		ExcludePaths:  []string{"/healthz"},
+	}, "admin-token"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	engine.GET("/books", ok)
+	engine.POST("/books", ok)
+	engine.GET("/healthz", ok)
+	return engine
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		result     ratelimit.Result
+		wantStatus int
+		wantHeader map[string]string
+	}{
+		{
+			name:       "Allowed Read",
+			method:     http.MethodGet,
+			result:     ratelimit.Result{Allowed: true, Limit: 300, Remaining: 299, Reset: 200 * time.Millisecond},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{
+				"RateLimit-Policy":    "300;w=60",
+				"RateLimit-Limit":     "300",
+				"RateLimit-Remaining": "299",
+				"RateLimit-Reset":     "1",
+				"Retry-After":         "",
+			},
+		},
+		{
+			name:       "Allowed Write",
+			method:     http.MethodPost,
+			result:     ratelimit.Result{Allowed: true, Limit: 60, Remaining: 10, Reset: 50 * time.Second},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{
+				"RateLimit-Policy":    "60;w=60",
+				"RateLimit-Limit":     "60",
+				"RateLimit-Remaining": "10",
+				"RateLimit-Reset":     "50",
+				"Retry-After":         "",
+			},
+		},
+		{
+			name:       "Rejected With Retry-After Rounded Up",
+			method:     http.MethodPost,
+			result:     ratelimit.Result{Allowed: false, Limit: 60, Remaining: 0, RetryAfter: 1500 * time.Millisecond, Reset: 60 * time.Second},
+			wantStatus: http.StatusTooManyRequests,
+			wantHeader: map[string]string{
+				"RateLimit-Limit":     "60",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"Retry-After":         "2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newRateLimitEngine(&stubStore{result: tt.result})
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, "/books", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			for name, want := range tt.wantHeader {
+				assert.Equal(t, want, w.Header().Get(name), name)
+			}
+			if tt.wantStatus == http.StatusTooManyRequests {
+				assert.JSONEq(t, `{"code":429,"message":"Too many requests, please try again later.","errors":null}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		wantKey string
+		wantReq int
+	}{
+		{name: "Reads By IP", method: http.MethodGet, wantKey: "read:ip:192.0.2.1", wantReq: 300},
+		{name: "Writes By IP", method: http.MethodPost, wantKey: "write:ip:192.0.2.1", wantReq: 60},
+		{name: "Known API Key", method: http.MethodGet, headers: map[string]string{APIKeyHeader: "known-key"}, wantKey: "read:api_key:"},
+		{name: "Unknown API Key Falls Back To IP", method: http.MethodGet, headers: map[string]string{APIKeyHeader: "made-up"}, wantKey: "read:ip:192.0.2.1"},
+		{name: "Admin Token", method: http.MethodPost, headers: map[string]string{"Authorization": "Bearer admin-token"}, wantKey: "write:user:admin"},
+		{name: "Wrong Admin Token Falls Back To IP", method: http.MethodPost, headers: map[string]string{"Authorization": "Bearer guess"}, wantKey: "write:ip:192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			store := &stubStore{result: ratelimit.Result{Allowed: true}}
+			engine := newRateLimitEngine(store)
+
+			req := httptest.NewRequest(tt.method, "/books", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+
+			if assert.Len(t, store.keys, 1) {
+				assert.Contains(t, store.keys[0], tt.wantKey)
+				assert.NotContains(t, store.keys[0], "known-key", "API keys are not kept in the store")
+				if tt.wantReq > 0 {
+					assert.Equal(t, tt.wantReq, store.limits[0].Requests)
+				}
+			}
+		})
+	}
+
+	// Define test for case Excluded Paths Are Not Limited
+	t.Run("Excluded Paths Are Not Limited", func(t *testing.T) {
+		store := &stubStore{}
+		w := httptest.NewRecorder()
+		newRateLimitEngine(store).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, store.keys)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	// Define test for case Store Errors Let Requests Through
+	t.Run("Store Errors Let Requests Through", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRateLimitEngine(&stubStore{err: errors.New("store down")}).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/books", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
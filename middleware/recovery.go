@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// Recovery recovers from panics, logs them with the stack trace and responds
+// with the standard error envelope instead of dropping the connection.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				// Handlers abort on purpose to drop the connection, e.g. when a stream fails
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				zerolog.Ctx(c.Request.Context()).Error().
+					Interface("panic", err).
+					Bytes("stack", debug.Stack()).
+					Msg("[Recovery] Recovered from panic")
+
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				helper.SendErrorResponse(c, http.StatusInternalServerError, "Internal server error.", nil)
+				c.Abort()
+			}
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// RequestBody rejects request bodies that are neither JSON nor JSON:API with 415 and bodies
+// larger than maxBytes with 413. Bodies without a Content-Length are cut off
+// at maxBytes while they are read.
+func RequestBody(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength == 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		logger := zerolog.Ctx(c.Request.Context())
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || (mediaType != gin.MIMEJSON && mediaType != helper.MIMEJSONAPI) {
+			logger.Error().Str("content_type", c.GetHeader("Content-Type")).Msg("[RequestBody] Unsupported content type")
+			helper.SendErrorResponse(c, http.StatusUnsupportedMediaType, "Content-Type must be JSON or JSON:API.", nil)
+			c.Abort()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			logger.Error().Int64("content_length", c.Request.ContentLength).Msg("[RequestBody] Request body too large")
+			helper.SendErrorResponse(c, http.StatusRequestEntityTooLarge, "Request body is too large.", nil)
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
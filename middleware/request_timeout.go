@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout puts a deadline on the request context, the one configured
+// for the matched route or the default, so database queries made with it are
+// cancelled once it passes. Handlers are not interrupted: the cancelled query
+// fails, and the error response is sent as a 504 by the helper.
+func RequestTimeout(cfg config.RequestTimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := cfg.Routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = cfg.Default
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
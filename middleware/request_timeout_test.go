@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowQuery stands in for a database call that takes d unless ctx is cancelled first, like database/sql does.
+func slowQuery(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	engine := gin.New()
+	engine.Use(RequestTimeout(config.RequestTimeoutConfig{
+		Default: 20 * time.Millisecond,
+		Routes:  map[string]time.Duration{"GET /stream": 0},
+	}))
+	handler := func(d time.Duration) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if err := slowQuery(c.Request.Context(), d); err != nil {
+				helper.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve books.", nil)
+				return
+			}
+			c.Status(http.StatusOK)
+		}
+	}
+	engine.GET("/slow", handler(time.Second))
+	engine.GET("/fast", handler(0))
+	engine.GET("/stream", handler(50*time.Millisecond))
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Query Past The Deadline Is A 504",
+			path:       "/slow",
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   `{"code":504,"error_code":"request_timeout","message":"The request took too long and was cancelled.","errors":null}`,
+		},
+		{name: "Query Within The Deadline", path: "/fast", wantStatus: http.StatusOK},
+		{name: "Route Without A Deadline", path: "/stream", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
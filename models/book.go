@@ -1,14 +1,31 @@
 package models
 
 import (
+	"strconv"
 	"time"
 )
 
 type Book struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title" binding:"required"`
-	Author    string    `json:"author" binding:"required"`
-	Year      int       `json:"year" binding:"required"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int       `json:"id" xml:"id" yaml:"id"`
+	Title     string    `json:"title" xml:"title" yaml:"title" binding:"required,max=255"`
+	Author    string    `json:"author" xml:"author" yaml:"author" binding:"required,max=255"`
+	Year      int       `json:"year" xml:"year" yaml:"year" binding:"required,notfuture"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at" yaml:"updated_at"`
+}
+
+// BookFields are the JSON names of the Book fields, which can be selected with ?fields=.
+var BookFields = []string{"id", "title", "author", "year", "created_at", "updated_at"}
+
+// BookType is the type of books in JSON:API documents.
+const BookType = "books"
+
+// ResourceType is the type of books in JSON:API documents.
+func (b Book) ResourceType() string {
+	return BookType
+}
+
+// ResourceID is the ID of the book in JSON:API documents.
+func (b Book) ResourceID() string {
+	return strconv.Itoa(b.ID)
 }
@@ -0,0 +1,14 @@
+package models
+
+// HealthCheck is a structure for the result of a single readiness check.
+type HealthCheck struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthStatus is a structure for health and readiness responses.
+type HealthStatus struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
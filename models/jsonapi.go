@@ -0,0 +1,59 @@
+package models
+
+// Resource is implemented by models that can be sent as JSON:API resource objects.
+type Resource interface {
+	ResourceType() string
+	ResourceID() string
+}
+
+// JSONAPIObject describes the JSON:API version a document follows.
+type JSONAPIObject struct {
+	Version string `json:"version"`
+}
+
+// JSONAPILinks holds the links of a JSON:API document or resource object.
+type JSONAPILinks struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// JSONAPIDocument is a successful response sent as application/vnd.api+json.
+type JSONAPIDocument struct {
+	JSONAPI JSONAPIObject  `json:"jsonapi"`
+	Data    interface{}    `json:"data"`
+	Links   *JSONAPILinks  `json:"links,omitempty"`
+	Meta    map[string]any `json:"meta,omitempty"`
+}
+
+// JSONAPIResource is a resource object, whose attributes hold every field but the ID.
+type JSONAPIResource struct {
+	Type       string        `json:"type"`
+	ID         string        `json:"id"`
+	Attributes interface{}   `json:"attributes,omitempty"`
+	Links      *JSONAPILinks `json:"links,omitempty"`
+}
+
+// JSONAPIErrorDocument is a failed response sent as application/vnd.api+json.
+type JSONAPIErrorDocument struct {
+	JSONAPI JSONAPIObject  `json:"jsonapi"`
+	Errors  []JSONAPIError `json:"errors"`
+}
+
+// JSONAPIError is a JSON:API error object.
+type JSONAPIError struct {
+	Status string              `json:"status"`
+	Code   string              `json:"code,omitempty"`
+	Title  string              `json:"title"`
+	Detail string              `json:"detail,omitempty"`
+	Source *JSONAPIErrorSource `json:"source,omitempty"`
+	Meta   map[string]any      `json:"meta,omitempty"`
+}
+
+// JSONAPIErrorSource points at the part of the request that caused an error.
+type JSONAPIErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LogLevelRequest is a structure for changing the log level at runtime.
+type LogLevelRequest struct {
+	Level     string `json:"level" binding:"required,oneof=debug info warn error"`
+	Component string `json:"component"`
+	Duration  string `json:"duration"`
+}
+
+// LogLevelOverride is a structure for a temporary log level.
+type LogLevelOverride struct {
+	Level     string    `json:"level"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LogLevelStatus is a structure for the current log levels.
+type LogLevelStatus struct {
+	Level      string                      `json:"level"`
+	Default    string                      `json:"default"`
+	ExpiresAt  *time.Time                  `json:"expires_at"`
+	Components map[string]LogLevelOverride `json:"components"`
+}
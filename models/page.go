@@ -0,0 +1,26 @@
+package models
+
+// Page selects one page of a list. A Size of 0 selects the whole list.
+type Page struct {
+	Number int
+	Size   int
+}
+
+// Offset returns the number of items before the page.
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Pagination describes the page of a list held by a response.
+type Pagination struct {
+	Page
+	Total int
+}
+
+// LastPage returns the number of the last page, which is 1 for an empty or unpaginated list.
+func (p Pagination) LastPage() int {
+	if p.Size == 0 || p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.Size - 1) / p.Size
+}
@@ -1,21 +1,51 @@
 package models
 
-// ResponseSuccess is a structure for successful responses.
+import "encoding/xml"
+
+// ResponseSuccess is a structure for successful responses. Links is only set for lists.
 type ResponseSuccess struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	XMLName xml.Name    `json:"-" xml:"response" yaml:"-"`
+	Code    int         `json:"code" xml:"code" yaml:"code"`
+	Message string      `json:"message" xml:"message" yaml:"message"`
+	Data    interface{} `json:"data" xml:"data" yaml:"data"`
+	Links   *HALLinks   `json:"_links,omitempty" xml:"_links,omitempty" yaml:"_links,omitempty"`
 }
 
-// ResponseError is a structure for failed responses.
+// ResponseError is a structure for failed responses. ErrorCode is only set
+// for errors a client may want to tell apart, such as a timeout.
 type ResponseError struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Errors  interface{} `json:"errors"`
+	XMLName   xml.Name    `json:"-" xml:"response" yaml:"-"`
+	Code      int         `json:"code" xml:"code" yaml:"code"`
+	ErrorCode string      `json:"error_code,omitempty" xml:"error_code,omitempty" yaml:"error_code,omitempty"`
+	Message   string      `json:"message" xml:"message" yaml:"message"`
+	Errors    interface{} `json:"errors" xml:"errors" yaml:"errors"`
 }
 
 // ValidationErrorDetail is a structure for validation error details.
 type ValidationErrorDetail struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Field   string `json:"field" xml:"field" yaml:"field"`
+	Message string `json:"message" xml:"message" yaml:"message"`
+}
+
+// Link is a HAL link.
+type Link struct {
+	Href string `json:"href" xml:"href,attr" yaml:"href"`
+}
+
+// NewLink returns a link to href, or nil when href is empty so the link is left out.
+func NewLink(href string) *Link {
+	if href == "" {
+		return nil
+	}
+	return &Link{Href: href}
+}
+
+// HALLinks are the HAL links of a resource or a list.
+type HALLinks struct {
+	Self       *Link `json:"self,omitempty" xml:"self,omitempty" yaml:"self,omitempty"`
+	Collection *Link `json:"collection,omitempty" xml:"collection,omitempty" yaml:"collection,omitempty"`
+	First      *Link `json:"first,omitempty" xml:"first,omitempty" yaml:"first,omitempty"`
+	Prev       *Link `json:"prev,omitempty" xml:"prev,omitempty" yaml:"prev,omitempty"`
+	Next       *Link `json:"next,omitempty" xml:"next,omitempty" yaml:"next,omitempty"`
+	Last       *Link `json:"last,omitempty" xml:"last,omitempty" yaml:"last,omitempty"`
 }
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStore(t *testing.T) {
+	// 10 requests per 10 seconds refill one token per second
+	limit := Limit{Requests: 10, Window: 10 * time.Second}
+
+	type step struct {
+		advance time.Duration
+		key     string
+		takes   int
+		want    Result
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "Allows A Full Burst",
+			steps: []step{
+				{key: "a", takes: 1, want: Result{Allowed: true, Limit: 10, Remaining: 9, Reset: time.Second}},
+				{key: "a", takes: 9, want: Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 10 * time.Second}},
+			},
+		},
+		{
+			name: "Rejects Over The Burst",
+			steps: []step{
+				{key: "a", takes: 10, want: Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 10 * time.Second}},
+				{key: "a", takes: 1, want: Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: time.Second, Reset: 10 * time.Second}},
+			},
+		},
+		{
+			name: "Tells When The Next Token Is Due",
+			steps: []step{
+				{key: "a", takes: 10, want: Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 10 * time.Second}},
+				{advance: 250 * time.Millisecond, key: "a", takes: 1, want: Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 750 * time.Millisecond, Reset: 9750 * time.Millisecond}},
+			},
+		},
+		{
+			name: "Refills Over Time",
+			steps: []step{
+				{key: "a", takes: 10, want: Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 10 * time.Second}},
+				{advance: 3 * time.Second, key: "a", takes: 1, want: Result{Allowed: true, Limit: 10, Remaining: 2, Reset: 8 * time.Second}},
+			},
+		},
+		{
+			name: "Does Not Refill Beyond The Burst",
+			steps: []step{
+				{key: "a", takes: 5, want: Result{Allowed: true, Limit: 10, Remaining: 5, Reset: 5 * time.Second}},
+				{advance: time.Hour, key: "a", takes: 1, want: Result{Allowed: true, Limit: 10, Remaining: 9, Reset: time.Second}},
+			},
+		},
+		{
+			name: "Keeps A Bucket Per Key",
+			steps: []step{
+				{key: "a", takes: 10, want: Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 10 * time.Second}},
+				{key: "b", takes: 1, want: Result{Allowed: true, Limit: 10, Remaining: 9, Reset: time.Second}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		// Define test for case tt.name
+		t.Run(tt.name, func(t *testing.T) {
+			store, clock := newTestStore()
+			for i, step := range tt.steps {
+				clock.now = clock.now.Add(step.advance)
+
+				var got Result
+				for n := 0; n < step.takes; n++ {
+					var err error
+					got, err = store.Take(context.Background(), step.key, limit)
+					assert.NoError(t, err)
+				}
+				assert.Equal(t, step.want, got, "step %d", i)
+			}
+		})
+	}
+
+	// Define test for case Sweeps Full Buckets
+	t.Run("Sweeps Full Buckets", func(t *testing.T) {
+		store, clock := newTestStore()
+		store.Take(context.Background(), "idle", limit)
+
+		// "busy" is emptied shortly before the sweep, "idle" has long been full again
+		clock.now = clock.now.Add(sweepInterval - 5*time.Second)
+		for n := 0; n < 10; n++ {
+			store.Take(context.Background(), "busy", limit)
+		}
+		assert.Len(t, store.buckets, 2)
+
+		clock.now = clock.now.Add(5 * time.Second)
+		result, _ := store.Take(context.Background(), "busy", limit)
+		assert.Len(t, store.buckets, 1)
+		assert.Contains(t, store.buckets, "busy")
+		assert.Equal(t, 4, result.Remaining)
+	})
+
+	// Define test for case Rejects Limits Without A Refill Rate
+	t.Run("Rejects Limits Without A Refill Rate", func(t *testing.T) {
+		for _, invalid := range []Limit{
+			{Requests: 0, Window: time.Minute},
+			{Requests: -1, Window: time.Minute},
+			{Requests: 10, Window: 0},
+			{Requests: 10, Window: -time.Second},
+		} {
+			store, _ := newTestStore()
+			result, err := store.Take(context.Background(), "client", invalid)
+			assert.Error(t, err, "%+v", invalid)
+			assert.Equal(t, Result{}, result)
+			assert.Empty(t, store.buckets)
+		}
+	})
+}
@@ -2,15 +2,22 @@ package repository
 
 import (
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/tracing"
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BookRepository interface {
-	GetAllBooks(ctx context.Context) ([]models.Book, error)
-	GetBookByID(ctx context.Context, id int) (*models.Book, error)
+	GetAllBooks(ctx context.Context, fields []string, page models.Page) ([]models.Book, error)
+	CountBooks(ctx context.Context) (int, error)
+	StreamBooks(ctx context.Context, fields []string, fn func(models.Book) error) error
+	GetBookByID(ctx context.Context, id int, fields []string) (*models.Book, error)
 	CreateBook(ctx context.Context, book *models.Book) error
 	UpdateBook(ctx context.Context, book *models.Book) error
 	DeleteBook(ctx context.Context, id int) error
@@ -25,92 +32,256 @@ func NewMySQLBookRepository(db *sql.DB) BookRepository {
 	return &mysqlBookRepository{DB: db}
 }
 
-func (r *mysqlBookRepository) GetAllBooks(ctx context.Context) ([]models.Book, error) {
-	query := "SELECT * FROM books"
-	rows, err := r.DB.QueryContext(ctx, query)
+// startSpan starts a client span for a single SQL statement.
+func startSpan(ctx context.Context, method, query string) (context.Context, trace.Span) {
+	return tracing.Tracer.Start(ctx, "BookRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBQueryText(query)),
+	)
+}
+
+// bookColumns returns the columns holding the given Book fields, all of them
+// when fields is empty, together with the matching scan destinations in book.
+// The id is always selected, as links and JSON:API resources are built from it
+// even when the client left it out of the fields.
+func bookColumns(book *models.Book, fields []string) ([]string, []any, error) {
+	if len(fields) == 0 {
+		fields = models.BookFields
+	}
+	selected := map[string]bool{"id": true}
+	for _, field := range fields {
+		selected[field] = true
+	}
+
+	var columns []string
+	var dest []any
+	// Columns are selected in table order, whatever order the fields were requested in
+	for _, field := range models.BookFields {
+		if !selected[field] {
+			continue
+		}
+		delete(selected, field)
+		columns = append(columns, field)
+		switch field {
+		case "id":
+			dest = append(dest, &book.ID)
+		case "title":
+			dest = append(dest, &book.Title)
+		case "author":
+			dest = append(dest, &book.Author)
+		case "year":
+			dest = append(dest, &book.Year)
+		case "created_at":
+			dest = append(dest, &book.CreatedAt)
+		case "updated_at":
+			dest = append(dest, &book.UpdatedAt)
+		}
+	}
+	for field := range selected {
+		return nil, nil, fmt.Errorf("unknown book field %q", field)
+	}
+	return columns, dest, nil
+}
+
+func (r *mysqlBookRepository) GetAllBooks(ctx context.Context, fields []string, page models.Page) ([]models.Book, error) {
+	var book models.Book
+	columns, dest, err := bookColumns(&book, fields)
 	if err != nil {
-		log.Error().Err(err).Msg("[BookRepository] Failed to get all books from database")
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to get all books from database")
+		return nil, err
+	}
+
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM books ORDER BY id"
+	var args []any
+	if page.Size > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, page.Size, page.Offset())
+	}
+	ctx, span := startSpan(ctx, "GetAllBooks", query)
+	defer span.End()
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to get all books from database")
 		return nil, err
 	}
 	defer rows.Close()
 
 	var books []models.Book
 	for rows.Next() {
-		var book models.Book
-		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt); err != nil {
-			log.Error().Err(err).Msg("[BookRepository] Failed to read book data from query results")
+		// dest points into book, so every row is scanned into it and then copied
+		book = models.Book{}
+		if err := rows.Scan(dest...); err != nil {
+			tracing.RecordError(span, err)
+			zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to read book data from query results")
 			return nil, err
 		}
 		books = append(books, book)
 	}
 
-	log.Info().Msg("[BookRepository] Successfully got all books from database")
+	if err := rows.Err(); err != nil {
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to get all books from database")
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Msg("[BookRepository] Successfully got all books from database")
 	return books, nil
 }
 
-func (r *mysqlBookRepository) GetBookByID(ctx context.Context, id int) (*models.Book, error) {
+// StreamBooks calls fn for every book as its row is read, so memory use does
+// not grow with the number of books. It stops at the first error returned by
+// fn, and when ctx is done, which also cancels the query.
+func (r *mysqlBookRepository) StreamBooks(ctx context.Context, fields []string, fn func(models.Book) error) error {
+	var book models.Book
+	columns, dest, err := bookColumns(&book, fields)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to stream books from database")
+		return err
+	}
+
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM books ORDER BY id"
+	ctx, span := startSpan(ctx, "StreamBooks", query)
+	defer span.End()
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to stream books from database")
+		return err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		book = models.Book{}
+		if err := rows.Scan(dest...); err != nil {
+			tracing.RecordError(span, err)
+			zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to read book data from query results")
+			return err
+		}
+		if err := fn(book); err != nil {
+			tracing.RecordError(span, err)
+			zerolog.Ctx(ctx).Warn().Err(err).Int("count", count).Msg("[BookRepository] Stopped streaming books")
+			return err
+		}
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Int("count", count).Msg("[BookRepository] Failed to stream books from database")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Int("count", count).Msg("[BookRepository] Successfully streamed books from database")
+	return nil
+}
+
+func (r *mysqlBookRepository) CountBooks(ctx context.Context) (int, error) {
+	query := "SELECT COUNT(*) FROM books"
+	ctx, span := startSpan(ctx, "CountBooks", query)
+	defer span.End()
+
+	var count int
+	if err := r.DB.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to count books in database")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *mysqlBookRepository) GetBookByID(ctx context.Context, id int, fields []string) (*models.Book, error) {
 	var book models.Book
-	query := "SELECT * FROM books WHERE id = ?"
-	err := r.DB.QueryRowContext(ctx, query, id).
-		Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt)
+	columns, dest, err := bookColumns(&book, fields)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Int("id", id).Msg("[BookRepository] Failed to get data from database")
+		return nil, err
+	}
+
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM books WHERE id = ?"
+	ctx, span := startSpan(ctx, "GetBookByID", query)
+	defer span.End()
+
+	err = r.DB.QueryRowContext(ctx, query, id).Scan(dest...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Warn().Int("id", id).Msg("[BookRepository] Data not found")
+			zerolog.Ctx(ctx).Warn().Int("id", id).Msg("[BookRepository] Data not found")
 			return nil, nil
 		}
-		log.Error().Err(err).Int("id", id).Msg("[BookRepository] Failed to get data from database")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Int("id", id).Msg("[BookRepository] Failed to get data from database")
 		return nil, err
 	}
 
-	log.Info().Int("id", id).Msg("[BookRepository] Successfully get data from database")
+	zerolog.Ctx(ctx).Info().Int("id", id).Msg("[BookRepository] Successfully get data from database")
 	return &book, nil
 }
 
 func (r *mysqlBookRepository) CreateBook(ctx context.Context, book *models.Book) error {
 	query := "INSERT INTO books (title, author, year, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	ctx, span := startSpan(ctx, "CreateBook", query)
+	defer span.End()
+
 	result, err := r.DB.ExecContext(ctx, query, book.Title, book.Author, book.Year, book.CreatedAt, book.UpdatedAt)
 	if err != nil {
-		log.Error().Err(err).Msg("[BookRepository] Failed to save data to database")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to save data to database")
 		return err
 	}
 	id, err := result.LastInsertId()
 	if err != nil {
-		log.Error().Err(err).Msg("[BookRepository] Failed to get newly created data ID")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to get newly created data ID")
 		return err
 	}
 	book.ID = int(id)
-	log.Info().Int("id", book.ID).Msg("[BookRepository] Successfully saved data to database")
+	zerolog.Ctx(ctx).Info().Int("id", book.ID).Msg("[BookRepository] Successfully saved data to database")
 	return nil
 }
 
 func (r *mysqlBookRepository) UpdateBook(ctx context.Context, book *models.Book) error {
 	query := "UPDATE books SET title = ?, author = ?, year = ?, updated_at = ? WHERE id = ?"
+	ctx, span := startSpan(ctx, "UpdateBook", query)
+	defer span.End()
+
 	_, err := r.DB.ExecContext(ctx, query, book.Title, book.Author, book.Year, book.UpdatedAt, book.ID)
 	if err != nil {
-		log.Error().Err(err).Int("id", book.ID).Msg("[BookRepository] Failed to update data in database")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Int("id", book.ID).Msg("[BookRepository] Failed to update data in database")
 		return err
 	}
 
-	log.Info().Int("id", book.ID).Msg("[BookRepository] Successfully updated data in database")
+	zerolog.Ctx(ctx).Info().Int("id", book.ID).Msg("[BookRepository] Successfully updated data in database")
 	return nil
 }
 
 func (r *mysqlBookRepository) DeleteBook(ctx context.Context, id int) error {
 	query := "DELETE FROM books WHERE id = ?"
+	ctx, span := startSpan(ctx, "DeleteBook", query)
+	defer span.End()
+
 	_, err := r.DB.ExecContext(ctx, query, id)
 	if err != nil {
-		log.Error().Err(err).Int("id", id).Msg("[BookRepository] Failed to delete data from database")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Int("id", id).Msg("[BookRepository] Failed to delete data from database")
 		return err
 	}
-	log.Info().Int("id", id).Msg("[BookRepository] Successfully deleted data from database")
+	zerolog.Ctx(ctx).Info().Int("id", id).Msg("[BookRepository] Successfully deleted data from database")
 	return nil
 }
 
 func (r *mysqlBookRepository) FindByTitle(ctx context.Context, title string) ([]models.Book, error) {
 	query := "SELECT id, title, author, year, created_at, updated_at FROM books WHERE title = ?"
+	ctx, span := startSpan(ctx, "FindByTitle", query)
+	defer span.End()
+
 	rows, err := r.DB.QueryContext(ctx, query, title)
 	if err != nil {
-		log.Error().Err(err).Msg("[BookRepository] Failed to get all books by title from database")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to get all books by title from database")
 		return nil, err
 	}
 	defer rows.Close()
@@ -119,17 +290,19 @@ func (r *mysqlBookRepository) FindByTitle(ctx context.Context, title string) ([]
 	for rows.Next() {
 		var book models.Book
 		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year, &book.CreatedAt, &book.UpdatedAt); err != nil {
-			log.Error().Err(err).Msg("[BookRepository] Failed to read book data from query results")
+			tracing.RecordError(span, err)
+			zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to read book data from query results")
 			return nil, err
 		}
 		books = append(books, book)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Error().Err(err).Msg("[BookRepository] Failed to get all books by title from database")
+		tracing.RecordError(span, err)
+		zerolog.Ctx(ctx).Error().Err(err).Msg("[BookRepository] Failed to get all books by title from database")
 		return nil, err
 	}
 
-	log.Info().Msg("[BookRepository] Successfully got all books by title from database")
+	zerolog.Ctx(ctx).Info().Msg("[BookRepository] Successfully got all books by title from database")
 	return books, nil
 }
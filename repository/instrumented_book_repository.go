@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/metrics"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"context"
+	"time"
+)
+
+// instrumentedBookRepository records the latency of every call to the wrapped repository.
+type instrumentedBookRepository struct {
+	next BookRepository
+}
+
+func NewInstrumentedBookRepository(next BookRepository) BookRepository {
+	return &instrumentedBookRepository{next: next}
+}
+
+func observe(method string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metrics.RepositoryQueryDuration.WithLabelValues(method, result).Observe(time.Since(start).Seconds())
+}
+
+func (r *instrumentedBookRepository) GetAllBooks(ctx context.Context, fields []string, page models.Page) ([]models.Book, error) {
+	start := time.Now()
+	books, err := r.next.GetAllBooks(ctx, fields, page)
+	observe("GetAllBooks", start, err)
+	return books, err
+}
+
+func (r *instrumentedBookRepository) StreamBooks(ctx context.Context, fields []string, fn func(models.Book) error) error {
+	start := time.Now()
+	err := r.next.StreamBooks(ctx, fields, fn)
+	observe("StreamBooks", start, err)
+	return err
+}
+
+func (r *instrumentedBookRepository) CountBooks(ctx context.Context) (int, error) {
+	start := time.Now()
+	count, err := r.next.CountBooks(ctx)
+	observe("CountBooks", start, err)
+	return count, err
+}
+
+func (r *instrumentedBookRepository) GetBookByID(ctx context.Context, id int, fields []string) (*models.Book, error) {
+	start := time.Now()
+	book, err := r.next.GetBookByID(ctx, id, fields)
+	observe("GetBookByID", start, err)
+	return book, err
+}
+
+func (r *instrumentedBookRepository) CreateBook(ctx context.Context, book *models.Book) error {
+	start := time.Now()
+	err := r.next.CreateBook(ctx, book)
+	observe("CreateBook", start, err)
+	return err
+}
+
+func (r *instrumentedBookRepository) UpdateBook(ctx context.Context, book *models.Book) error {
+	start := time.Now()
+	err := r.next.UpdateBook(ctx, book)
+	observe("UpdateBook", start, err)
+	return err
+}
+
+func (r *instrumentedBookRepository) DeleteBook(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteBook(ctx, id)
+	observe("DeleteBook", start, err)
+	return err
+}
+
+func (r *instrumentedBookRepository) FindByTitle(ctx context.Context, title string) ([]models.Book, error) {
+	start := time.Now()
+	books, err := r.next.FindByTitle(ctx, title)
+	observe("FindByTitle", start, err)
+	return books, err
+}
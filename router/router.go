@@ -0,0 +1,40 @@
+package router
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/docs"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/handler"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/metrics"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers groups the handlers served by the API.
+type Handlers struct {
+	Book   handler.BookRoutes
+	Admin  *handler.AdminHandler
+	Health *handler.HealthHandler
+}
+
+// RegisterRoutes registers every route of the API. Each route must be described in docs/openapi.json.
+func RegisterRoutes(router *gin.Engine, h Handlers, apiConfig config.APIConfig) {
+	router.GET("/healthz", h.Health.Liveness)
+	router.GET("/readyz", h.Health.Readiness)
+
+	v1 := router.Group("/api/v1")
+	handler.RegisterBookRoutes(v1, h.Book)
+
+	// Keep the unversioned paths as deprecated aliases of v1
+	if apiConfig.LegacyRoutes {
+		legacy := router.Group("", middleware.Deprecated("/api/v1", apiConfig.LegacyRoutesSunset))
+		handler.RegisterBookRoutes(legacy, h.Book)
+	}
+
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/docs/*filepath", docs.Handler)
+
+	admin := router.Group("/admin", middleware.AdminAuth(h.Admin.Config.Token))
+	admin.GET("/log-level", h.Admin.GetLogLevel)
+	admin.PUT("/log-level", h.Admin.UpdateLogLevel)
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/metrics"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
 	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/repository"
 	"context"
 	"errors"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 // Define the ErrBookExists error
@@ -14,9 +17,16 @@ var ErrBookExists = errors.New("ErrBookExists")
 // Define the errBookNotFound error
 var errBookNotFound = errors.New("errBookNotFound")
 
+// ErrYearInFuture is returned when a book is saved with a year of publication in the future
+var ErrYearInFuture = errors.New("ErrYearInFuture")
+
 type BookService interface {
-	GetAllBooks(ctx context.Context) ([]models.Book, error)
-	GetBookByID(ctx context.Context, id int) (*models.Book, error)
+	// GetAllBooks and GetBookByID load only the given fields of models.BookFields, or all of them when fields is empty.
+	// GetAllBooks also returns the total number of books, which is larger than the page when the list is paginated.
+	GetAllBooks(ctx context.Context, fields []string, page models.Page) ([]models.Book, int, error)
+	GetBookByID(ctx context.Context, id int, fields []string) (*models.Book, error)
+	// StreamBooks calls fn for every book as it is read from the database, stopping at the first error.
+	StreamBooks(ctx context.Context, fields []string, fn func(models.Book) error) error
 	CreateBook(ctx context.Context, book *models.Book) error
 	UpdateBook(ctx context.Context, book *models.Book) error
 	DeleteBook(ctx context.Context, id int) error
@@ -30,18 +40,35 @@ func NewBookService(repo repository.BookRepository) BookService {
 	return &bookService{repo: repo}
 }
 
-func (s *bookService) GetAllBooks(ctx context.Context) ([]models.Book, error) {
-	return s.repo.GetAllBooks(ctx)
+func (s *bookService) GetAllBooks(ctx context.Context, fields []string, page models.Page) ([]models.Book, int, error) {
+	books, err := s.repo.GetAllBooks(ctx, fields, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if page.Size == 0 {
+		return books, len(books), nil
+	}
+
+	total, err := s.repo.CountBooks(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
+
+func (s *bookService) StreamBooks(ctx context.Context, fields []string, fn func(models.Book) error) error {
+	return s.repo.StreamBooks(ctx, fields, fn)
 }
 
-func (s *bookService) GetBookByID(ctx context.Context, id int) (*models.Book, error) {
+func (s *bookService) GetBookByID(ctx context.Context, id int, fields []string) (*models.Book, error) {
 	// Check if a book with that ID exists
-	book, err := s.repo.GetBookByID(ctx, id)
+	book, err := s.repo.GetBookByID(ctx, id, fields)
 	if err != nil {
 		return nil, err
 	}
 
 	if book == nil {
+		zerolog.Ctx(ctx).Warn().Int("id", id).Msg("[BookService] Book not found")
 		return nil, errBookNotFound
 	}
 
@@ -49,12 +76,17 @@ func (s *bookService) GetBookByID(ctx context.Context, id int) (*models.Book, er
 }
 
 func (s *bookService) CreateBook(ctx context.Context, book *models.Book) error {
+	if err := checkYear(ctx, book); err != nil {
+		return err
+	}
+
 	// Check if a book with the same title already exists
 	existingBooks, err := s.repo.FindByTitle(ctx, book.Title)
 	if err != nil {
 		return err
 	}
 	if len(existingBooks) > 0 {
+		zerolog.Ctx(ctx).Warn().Str("title", book.Title).Msg("[BookService] Book with the same title already exists")
 		return ErrBookExists
 	}
 
@@ -62,46 +94,63 @@ func (s *bookService) CreateBook(ctx context.Context, book *models.Book) error {
 	book.CreatedAt = time.Now()
 	book.UpdatedAt = time.Now()
 
-	return s.repo.CreateBook(ctx, book)
+	if err := s.repo.CreateBook(ctx, book); err != nil {
+		return err
+	}
+
+	metrics.BooksCreatedTotal.Inc()
+	return nil
 }
 
 func (s *bookService) UpdateBook(ctx context.Context, book *models.Book) error {
+	if err := checkYear(ctx, book); err != nil {
+		return err
+	}
+
 	// Check if a book with that ID exists
-	existingBook, err := s.repo.GetBookByID(ctx, book.ID)
+	existingBook, err := s.repo.GetBookByID(ctx, book.ID, []string{"id", "created_at"})
 	if err != nil {
 		return err
 	}
 
 	if existingBook == nil {
+		zerolog.Ctx(ctx).Warn().Int("id", book.ID).Msg("[BookService] Book not found, cannot update")
 		return errBookNotFound
 	}
 
 	book.CreatedAt = existingBook.CreatedAt
 	book.UpdatedAt = time.Now()
 
-	// Validation that the year cannot be in the future
-	if book.Year > time.Now().Year() {
-		return errors.New("errTheYearCannotBeInTheFuture")
-	}
-
 	return s.repo.UpdateBook(ctx, book)
 }
 
 func (s *bookService) DeleteBook(ctx context.Context, id int) error {
 	// Check if a book with that ID exists
-	existingBook, err := s.repo.GetBookByID(ctx, id)
+	existingBook, err := s.repo.GetBookByID(ctx, id, []string{"id", "year"})
 	if err != nil {
 		return err
 	}
 
 	if existingBook == nil {
+		zerolog.Ctx(ctx).Warn().Int("id", id).Msg("[BookService] Book not found, cannot delete")
 		return errBookNotFound
 	}
 
 	// For example, books older than 10 years should not be deleted
 	if time.Now().Year()-existingBook.Year > 10 {
+		zerolog.Ctx(ctx).Warn().Int("id", id).Int("year", existingBook.Year).Msg("[BookService] Book is older than 10 years, cannot delete")
+		metrics.BookDeleteRejectionsTotal.WithLabelValues("older_than_10_years").Inc()
 		return errors.New("errBooksOlderThan10Years")
 	}
 
 	return s.repo.DeleteBook(ctx, id)
 }
+
+// checkYear rejects a year of publication in the future, also for callers that bypass the request validation.
+func checkYear(ctx context.Context, book *models.Book) error {
+	if book.Year > time.Now().Year() {
+		zerolog.Ctx(ctx).Warn().Int("id", book.ID).Int("year", book.Year).Msg("[BookService] Year of publication is in the future")
+		return ErrYearInFuture
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracedBookService wraps every call to the underlying service in a span.
+type tracedBookService struct {
+	next BookService
+}
+
+func NewTracedBookService(next BookService) BookService {
+	return &tracedBookService{next: next}
+}
+
+func (s *tracedBookService) GetAllBooks(ctx context.Context, fields []string, page models.Page) ([]models.Book, int, error) {
+	ctx, span := tracing.Tracer.Start(ctx, "BookService.GetAllBooks")
+	defer span.End()
+	span.SetAttributes(
+		attribute.StringSlice("book.fields", fields),
+		attribute.Int("page.number", page.Number),
+		attribute.Int("page.size", page.Size),
+	)
+
+	books, total, err := s.next.GetAllBooks(ctx, fields, page)
+	tracing.RecordError(span, err)
+	return books, total, err
+}
+
+func (s *tracedBookService) StreamBooks(ctx context.Context, fields []string, fn func(models.Book) error) error {
+	ctx, span := tracing.Tracer.Start(ctx, "BookService.StreamBooks")
+	defer span.End()
+	span.SetAttributes(attribute.StringSlice("book.fields", fields))
+
+	err := s.next.StreamBooks(ctx, fields, fn)
+	tracing.RecordError(span, err)
+	return err
+}
+
+func (s *tracedBookService) GetBookByID(ctx context.Context, id int, fields []string) (*models.Book, error) {
+	ctx, span := tracing.Tracer.Start(ctx, "BookService.GetBookByID")
+	defer span.End()
+	span.SetAttributes(attribute.Int("book.id", id), attribute.StringSlice("book.fields", fields))
+
+	book, err := s.next.GetBookByID(ctx, id, fields)
+	tracing.RecordError(span, err)
+	return book, err
+}
+
+func (s *tracedBookService) CreateBook(ctx context.Context, book *models.Book) error {
+	ctx, span := tracing.Tracer.Start(ctx, "BookService.CreateBook")
+	defer span.End()
+
+	err := s.next.CreateBook(ctx, book)
+	span.SetAttributes(attribute.Int("book.id", book.ID))
+	tracing.RecordError(span, err)
+	return err
+}
+
+func (s *tracedBookService) UpdateBook(ctx context.Context, book *models.Book) error {
+	ctx, span := tracing.Tracer.Start(ctx, "BookService.UpdateBook")
+	defer span.End()
+	span.SetAttributes(attribute.Int("book.id", book.ID))
+
+	err := s.next.UpdateBook(ctx, book)
+	tracing.RecordError(span, err)
+	return err
+}
+
+func (s *tracedBookService) DeleteBook(ctx context.Context, id int) error {
+	ctx, span := tracing.Tracer.Start(ctx, "BookService.DeleteBook")
+	defer span.End()
+	span.SetAttributes(attribute.Int("book.id", id))
+
+	err := s.next.DeleteBook(ctx, id)
+	tracing.RecordError(span, err)
+	return err
+}
@@ -202,6 +202,9 @@ func TestIntegrationGetAllBooks(t *testing.T) {
 			"code":    float64(http.StatusOK),
 			"message": "Successfully got all data.",
 			"data":    expectedData,
+			"_links": map[string]interface{}{
+				"self": map[string]interface{}{"href": "http://example.com/books"},
+			},
 		}
 
 		// If you want to show log the result expected data actual data turn on this line below
@@ -487,17 +490,23 @@ func TestIntegrationUpdateBook(t *testing.T) {
 		var response map[string]interface{}
 		json.Unmarshal(rec.Body.Bytes(), &response)
 
+		// The notfuture validator rejects the year before the service is called
 		expectedResponse := map[string]interface{}{
-			"code":    float64(http.StatusBadRequest),
-			"message": "Year of publication cannot be in the future, cannot update.",
-			"errors":  nil,
+			"code":    float64(http.StatusUnprocessableEntity),
+			"message": "validation error",
+			"errors": []interface{}{
+				map[string]interface{}{
+					"field":   "year",
+					"message": "Must not be in the future",
+				},
+			},
 		}
 
 		// If you want to show log the result expected data actual data turn on this line below
 		// log.Info().Msgf("Expected => %v", expectedResponse)
 		// log.Info().Msgf("Actual => %v", response)
 
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 		assert.Equal(t, expectedResponse, response)
 	})
 }
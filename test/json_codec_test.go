@@ -0,0 +1,98 @@
+package test
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/jsoncodec"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var benchmarkCodecs = []jsoncodec.Codec{jsoncodec.Std, jsoncodec.Sonic, jsoncodec.GoJSON}
+
+func largeBookList(n int) []models.Book {
+	books := make([]models.Book, n)
+	now := time.Now()
+	for i := range books {
+		books[i] = models.Book{
+			ID:        i + 1,
+			Title:     fmt.Sprintf("Book number %d", i+1),
+			Author:    fmt.Sprintf("Author number %d", i%100),
+			Year:      1950 + i%75,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return books
+}
+
+// Run with: go test ./test -run '^$' -bench JSONCodecs -benchmem
+func BenchmarkJSONCodecs(b *testing.B) {
+	books := largeBookList(10000)
+
+	for _, codec := range benchmarkCodecs {
+		data, err := codec.Marshal(books)
+		if err != nil {
+			b.Fatalf("Error encoding books with %s: %v", codec.Name, err)
+		}
+
+		b.Run(codec.Name+"/Marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.Marshal(books); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(codec.Name+"/Encode", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := codec.NewEncoder(io.Discard).Encode(books); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(codec.Name+"/Unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				var decoded []models.Book
+				if err := codec.Unmarshal(data, &decoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		// The list response streams the items one by one
+		b.Run(codec.Name+"/ListResponse", func(b *testing.B) {
+			gin.SetMode(gin.TestMode)
+			previous := helper.JSONCodec
+			helper.JSONCodec = codec
+			defer func() { helper.JSONCodec = previous }()
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c, _ := gin.CreateTestContext(discardWriter{httptest.NewRecorder()})
+				c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/books", nil)
+				helper.SendListResponse(c, http.StatusOK, "Successfully got all data.", books, models.Pagination{Total: len(books)})
+			}
+		})
+	}
+}
+
+// discardWriter keeps the headers of the recorder but drops the body, so only encoding is measured.
+type discardWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w discardWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
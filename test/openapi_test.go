@@ -0,0 +1,221 @@
+package test
+
+import (
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/config"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/docs"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/handler"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/helper"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/middleware"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/models"
+	"RESTful-APIs-with-Go-and-MySQL-Using-the-Repository-Pattern/router"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Gin path parameters such as :id become {id} in OpenAPI paths
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+func registeredRoutes() []string {
+	engine := gin.New()
+	router.RegisterRoutes(engine, router.Handlers{
+		Book:   handler.NewBookHandler(nil),
+		Admin:  handler.NewAdminHandler(nil, config.AdminConfig{}),
+		Health: handler.NewHealthHandler(nil, 0),
+	}, config.APIConfig{LegacyRoutes: true})
+
+	var routes []string
+	for _, route := range engine.Routes() {
+		// The documentation itself is not part of the API
+		if strings.HasPrefix(route.Path, "/docs/") {
+			continue
+		}
+		routes = append(routes, route.Method+" "+ginParam.ReplaceAllString(route.Path, "{$1}"))
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+func documentedRoutes(t *testing.T) []string {
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(docs.Spec, &spec); err != nil {
+		t.Fatalf("Error parsing OpenAPI document: %v", err)
+	}
+	assert.Equal(t, "3.1.0", spec.OpenAPI)
+
+	var routes []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "delete", "patch", "head", "options":
+				routes = append(routes, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// renderedFormats returns the media types responses are written in, one for every
+// format in helper.EnvelopeFormats, as several of them are aliases of each other.
+func renderedFormats(t *testing.T) []string {
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		helper.SendSuccessResponse(c, http.StatusOK, "ok", nil)
+	})
+
+	seen := map[string]bool{}
+	for _, format := range helper.EnvelopeFormats {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", format)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("Error parsing the Content-Type of %s: %v", format, err)
+		}
+		seen[mediaType] = true
+	}
+
+	var formats []string
+	for format := range seen {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// documentedFormats returns the media types of every response in the document
+// that is sent in the negotiated format, keyed by where the response is.
+// Streams, responses that are never JSON such as the metrics, and the 406
+// response, which is always JSON, are left out, as is CSV, which only lists can
+// be sent in.
+func documentedFormats(t *testing.T) map[string][]string {
+	type response struct {
+		Content map[string]json.RawMessage `json:"content"`
+	}
+	var spec struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Responses map[string]response `json:"responses"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(docs.Spec, &spec); err != nil {
+		t.Fatalf("Error parsing OpenAPI document: %v", err)
+	}
+
+	result := map[string][]string{}
+	add := func(where string, r response) {
+		if r.Content[gin.MIMEJSON] == nil || r.Content[helper.MIMENDJSON] != nil {
+			return
+		}
+		var formats []string
+		for format := range r.Content {
+			if format != helper.MIMECSV {
+				formats = append(formats, format)
+			}
+		}
+		sort.Strings(formats)
+		result[where] = formats
+	}
+
+	for name, r := range spec.Components.Responses {
+		if name != "NotAcceptable" {
+			add("#/components/responses/"+name, r)
+		}
+	}
+	for path, item := range spec.Paths {
+		for method, raw := range item {
+			var operation struct {
+				Responses map[string]response `json:"responses"`
+			}
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				continue
+			}
+			for status, r := range operation.Responses {
+				add(strings.ToUpper(method)+" "+path+" "+status, r)
+			}
+		}
+	}
+	return result
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	// Define test for case Routes Match The Spec
+	t.Run("Routes Match The Spec", func(t *testing.T) {
+		assert.Equal(t, documentedRoutes(t), registeredRoutes())
+	})
+
+	// Define test for case Response Formats Match The Spec
+	t.Run("Response Formats Match The Spec", func(t *testing.T) {
+		formats := renderedFormats(t)
+		for where, documented := range documentedFormats(t) {
+			assert.Equal(t, formats, documented, where)
+		}
+	})
+
+	// Define test for case Fields Patterns Match The Handler
+	t.Run("Fields Patterns Match The Handler", func(t *testing.T) {
+		openAPIRouter, err := docs.NewRouter()
+		if err != nil {
+			t.Fatalf("Error loading OpenAPI document: %v", err)
+		}
+
+		// The handler side alone, and the same behind the validator, must accept the same values
+		handle := func(c *gin.Context) {
+			if _, ok := helper.GetFieldsParam(c, models.BookType, models.BookFields); ok {
+				c.Status(http.StatusOK)
+			}
+		}
+		plain := gin.New()
+		plain.GET("/api/v1/books", handle)
+		validated := gin.New()
+		validated.Use(middleware.OpenAPIValidator(openAPIRouter, false))
+		validated.GET("/api/v1/books", handle)
+
+		values := []string{"", ",", "id,", ",id", "isbn", "id,isbn", "id title", "id,id", "title, author", " title ,author ", "title,\tyear"}
+		values = append(values, models.BookFields...)
+		values = append(values, strings.Join(models.BookFields, ","), strings.Join(models.BookFields, " , "))
+
+		for _, param := range []string{"fields", "fields[" + models.BookType + "]"} {
+			for _, value := range values {
+				target := "/api/v1/books?" + url.Values{param: {value}}.Encode()
+
+				want := httptest.NewRecorder()
+				plain.ServeHTTP(want, httptest.NewRequest(http.MethodGet, target, nil))
+				got := httptest.NewRecorder()
+				validated.ServeHTTP(got, httptest.NewRequest(http.MethodGet, target, nil))
+				assert.Equal(t, want.Code, got.Code, "%s=%q", param, value)
+			}
+		}
+	})
+
+	// Define test for case Serves Spec And Swagger UI
+	t.Run("Serves Spec And Swagger UI", func(t *testing.T) {
+		engine := gin.New()
+		engine.GET("/docs/*filepath", docs.Handler)
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/openapi.json", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, string(docs.Spec), w.Body.String())
+
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "swagger-ui")
+	})
+}